@@ -0,0 +1,180 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lambher/video-game/conf"
+	"github.com/lambher/video-game/models"
+)
+
+// Client joins a server with the same protocol as the game and keeps a copy
+// of the world up to date, without rendering anything. The game renders on
+// top of it; scripted bots and load generators can use it on their own.
+//
+// The Listener callbacks run on the goroutine calling Listen, while it
+// updates the world. Other goroutines must not touch World, Player or the
+// Send methods concurrently: wrap that code in Do.
+type Client struct {
+	world    *models.World
+	listener Listener
+	conn     net.Conn
+	lock     sync.Mutex
+}
+
+func NewClient(listener Listener) (*Client, error) {
+	weapons, err := models.LoadWeapons(conf.WeaponsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	world := &models.World{
+		Weapons: weapons,
+	}
+	world.Subscribe(models.ListenerHandler(listener))
+
+	return &Client{
+		world:    world,
+		listener: listener,
+	}, nil
+}
+
+func (c *Client) World() *models.World {
+	return c.world
+}
+
+// Player returns the player of this client, nil until the server sent it.
+func (c *Client) Player() *models.Player {
+	return c.world.Player
+}
+
+// Connect says hello to the server at addr and loads the map it answers
+// with. Call Listen afterwards to receive the rest of the world.
+func (c *Client) Connect(addr string) error {
+	var err error
+	c.conn, err = net.Dial("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.conn.Write([]byte("hello"))
+	if err != nil {
+		return err
+	}
+
+	p := make([]byte, conf.MaxPacketSize)
+	n, err := bufio.NewReader(c.conn).Read(p)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.parse(string(p[:n]))
+}
+
+// Listen handles the messages of the server until the connection is closed.
+func (c *Client) Listen() {
+	for {
+		p := make([]byte, conf.MaxPacketSize)
+		n, err := bufio.NewReader(c.conn).Read(p)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			fmt.Printf("Some error %v\n", err)
+			continue
+		}
+		c.lock.Lock()
+		err = c.parse(string(p[:n]))
+		c.lock.Unlock()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// Do runs f while no message from the server is being handled, so f can use
+// the world and the Send methods while Listen runs on another goroutine. Do
+// must not be called from a Listener callback.
+func (c *Client) Do(f func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	f()
+}
+
+// Update moves the world forward between two server refreshes.
+func (c *Client) Update(deltaTime time.Duration) {
+	c.world.UpdatePositions(deltaTime)
+}
+
+// SendPlayer sends the state of the player, its name included.
+func (c *Client) SendPlayer() {
+	playerData, err := c.world.GetPlayerData()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	c.send("refresh_player", playerData)
+}
+
+// SendMove sends the keys currently pressed by the player, set with the
+// Move and Turn methods of models.Player.
+func (c *Client) SendMove() {
+	moveData, err := c.world.GetPlayerMoveData()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	c.send("move", moveData)
+}
+
+func (c *Client) SendFire() {
+	c.send("fire", nil)
+}
+
+func (c *Client) SendWeapon(name string) {
+	weaponData, err := json.Marshal(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	c.send("weapon", weaponData)
+}
+
+func (c *Client) SendReload() {
+	c.send("reload", nil)
+}
+
+// SendExit leaves the server and closes the connection.
+func (c *Client) SendExit() {
+	if c.conn == nil {
+		return
+	}
+	_, err := c.conn.Write([]byte("exit"))
+	if err != nil {
+		fmt.Println(err)
+	}
+	c.conn.Close()
+}
+
+func (c *Client) send(kind string, payload []byte) {
+	if c.conn == nil {
+		return
+	}
+
+	data := make([]byte, 0)
+
+	data = append(data, []byte(kind+"\n")...)
+	data = append(data, payload...)
+
+	_, err := c.conn.Write(data)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,547 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lambher/video-game/models"
+)
+
+func (c *Client) parse(message string) error {
+	messages := strings.Split(message, "\n")
+	if len(messages) <= 1 {
+		return nil
+	}
+	switch messages[0] {
+	case "map":
+		return c.handleMap([]byte(messages[1]))
+	case "you":
+		c.handleYou([]byte(messages[1]))
+	case "add_player":
+		c.handleAddPlayer([]byte(messages[1]))
+	case "exit":
+		c.handleExit([]byte(messages[1]))
+	case "refresh_player":
+		c.handleRefreshPlayer([]byte(messages[1]))
+	case "ammo":
+		c.handleAmmo([]byte(messages[1]))
+	case "add_bullet":
+		c.handleAddBullet([]byte(messages[1]))
+	case "add_missile":
+		c.handleAddMissile([]byte(messages[1]))
+	case "refresh_missile":
+		c.handleRefreshMissile([]byte(messages[1]))
+	case "hitscan":
+		c.handleHitscan([]byte(messages[1]))
+	case "explosion":
+		c.handleExplosion([]byte(messages[1]))
+	case "add_pickup":
+		c.handleAddPickup([]byte(messages[1]))
+	case "pickup":
+		c.handlePickup([]byte(messages[1]))
+	case "add_flag":
+		c.handleAddFlag([]byte(messages[1]))
+	case "flag":
+		c.handleFlag([]byte(messages[1]))
+	case "add_zone":
+		c.handleAddZone([]byte(messages[1]))
+	case "refresh_zone":
+		c.handleRefreshZone([]byte(messages[1]))
+	case "add_enemy":
+		c.handleAddEnemy([]byte(messages[1]))
+	case "refresh_enemy":
+		c.handleRefreshEnemy([]byte(messages[1]))
+	case "wave":
+		c.handleWave([]byte(messages[1]))
+	case "checkpoint":
+		c.handleCheckpoint([]byte(messages[1]))
+	case "ghost":
+		c.handleGhost([]byte(messages[1]))
+	case "safe_zone":
+		c.handleSafeZone([]byte(messages[1]))
+	case "match":
+		c.handleMatch([]byte(messages[1]))
+	case "scoreboard":
+		c.handleScoreboard([]byte(messages[1]))
+	case "remove_model":
+		c.handleRemoveModel([]byte(messages[1]))
+	case "collide":
+		c.handleCollide([]byte(messages[1]))
+	case "damage":
+		c.handleDamage([]byte(messages[1]))
+	case "health":
+		c.handleHealth([]byte(messages[1]))
+	case "death":
+		c.handleDeath([]byte(messages[1]))
+	case "respawn":
+		c.handleRespawn([]byte(messages[1]))
+	}
+	return nil
+}
+
+func (c *Client) handleMap(data []byte) error {
+	var info models.MapInfo
+
+	err := json.Unmarshal(data, &info)
+	if err != nil {
+		return err
+	}
+
+	m, err := models.LoadMap(info.ID)
+	if err != nil {
+		return err
+	}
+	if m.Checksum != info.Checksum {
+		return fmt.Errorf("map %s does not match the server version", info.ID)
+	}
+
+	c.world.Level = m.GetLevel()
+	c.world.Gates = m.Gates
+	c.world.NoWeapons = m.DisableWeapons
+	c.listener.OnMap(m)
+	return nil
+}
+
+func (c *Client) handleRefreshPlayer(data []byte) {
+	var player models.Player
+
+	err := json.Unmarshal(data, &player)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if player.Position == nil {
+		fmt.Println("player position is null")
+		return
+	}
+	if p := c.world.GetPlayer(player.GetID()); p != nil {
+		p.Refresh(player)
+		p.RefreshState(player)
+	}
+}
+
+func (c *Client) handleAmmo(data []byte) {
+	var state models.WeaponState
+
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if c.world.Player != nil {
+		c.world.Player.SetWeaponState(&state)
+	}
+}
+
+func (c *Client) handleAddBullet(data []byte) {
+	var bullet models.Bullet
+
+	err := json.Unmarshal(data, &bullet)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if bullet.Position == nil || bullet.Velocity == nil {
+		fmt.Println("bullet position is null")
+		return
+	}
+
+	c.world.AddBullet(&bullet)
+}
+
+func (c *Client) handleAddMissile(data []byte) {
+	var missile models.Missile
+
+	err := json.Unmarshal(data, &missile)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if missile.Position == nil || missile.Velocity == nil {
+		fmt.Println("missile position is null")
+		return
+	}
+
+	c.world.AddMissile(&missile)
+}
+
+func (c *Client) handleRefreshMissile(data []byte) {
+	var missile models.Missile
+
+	err := json.Unmarshal(data, &missile)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if missile.Position == nil || missile.Velocity == nil {
+		fmt.Println("missile position is null")
+		return
+	}
+	if m := c.world.GetMissile(missile.GetID()); m != nil {
+		m.Refresh(missile)
+	}
+}
+
+func (c *Client) handleHitscan(data []byte) {
+	var event models.HitscanEvent
+
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if event.From == nil || event.To == nil {
+		fmt.Println("hitscan position is null")
+		return
+	}
+
+	c.listener.OnHitscan(&event)
+}
+
+func (c *Client) handleExplosion(data []byte) {
+	var event models.ExplosionEvent
+
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if event.Position == nil {
+		fmt.Println("explosion position is null")
+		return
+	}
+
+	c.listener.OnExplosion(&event)
+}
+
+func (c *Client) handleAddPickup(data []byte) {
+	var pickup models.Pickup
+
+	err := json.Unmarshal(data, &pickup)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if pickup.Position == nil {
+		fmt.Println("pickup position is null")
+		return
+	}
+
+	c.world.AddPickup(&pickup)
+}
+
+func (c *Client) handlePickup(data []byte) {
+	var event models.PickupEvent
+
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if pickup := c.world.GetPickup(event.Pickup); pickup != nil {
+		pickup.SetActive(event.Active, c.world.GetPlayer(event.Player))
+	}
+}
+
+func (c *Client) handleAddFlag(data []byte) {
+	var flag models.Flag
+
+	err := json.Unmarshal(data, &flag)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if flag.Base == nil || flag.Position == nil {
+		fmt.Println("flag position is null")
+		return
+	}
+
+	c.world.AddFlag(&flag)
+}
+
+func (c *Client) handleFlag(data []byte) {
+	var event models.FlagEvent
+
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if flag := c.world.GetFlag(event.Flag); flag != nil {
+		flag.Apply(&event)
+	}
+}
+
+func (c *Client) handleAddZone(data []byte) {
+	var zone models.Zone
+
+	err := json.Unmarshal(data, &zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if zone.Position == nil {
+		fmt.Println("zone position is null")
+		return
+	}
+
+	c.world.AddZone(&zone)
+}
+
+func (c *Client) handleRefreshZone(data []byte) {
+	var zone models.Zone
+
+	err := json.Unmarshal(data, &zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if z := c.world.GetZone(zone.GetID()); z != nil {
+		z.Refresh(zone)
+	}
+}
+
+func (c *Client) handleAddEnemy(data []byte) {
+	var enemy models.Enemy
+
+	err := json.Unmarshal(data, &enemy)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if enemy.Position == nil || enemy.Velocity == nil || enemy.Direction == nil {
+		fmt.Println("enemy position is null")
+		return
+	}
+
+	c.world.AddEnemy(&enemy)
+}
+
+func (c *Client) handleRefreshEnemy(data []byte) {
+	var enemy models.Enemy
+
+	err := json.Unmarshal(data, &enemy)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if enemy.Position == nil || enemy.Velocity == nil || enemy.Direction == nil {
+		fmt.Println("enemy position is null")
+		return
+	}
+	if e := c.world.GetEnemy(enemy.GetID()); e != nil {
+		e.Refresh(enemy)
+	}
+}
+
+func (c *Client) handleWave(data []byte) {
+	var state models.WaveState
+
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c.listener.OnWave(&state)
+}
+
+func (c *Client) handleCheckpoint(data []byte) {
+	var event models.CheckpointEvent
+
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c.listener.OnCheckpoint(&event)
+}
+
+func (c *Client) handleGhost(data []byte) {
+	var ghost models.Ghost
+
+	err := json.Unmarshal(data, &ghost)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c.listener.OnGhost(&ghost)
+}
+
+func (c *Client) handleSafeZone(data []byte) {
+	var zone models.SafeZone
+
+	err := json.Unmarshal(data, &zone)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if zone.Center == nil {
+		fmt.Println("safe zone center is null")
+		return
+	}
+
+	if c.world.SafeZone != nil {
+		c.world.SafeZone.Refresh(zone)
+		return
+	}
+	c.world.SafeZone = &zone
+	c.listener.OnSafeZone(c.world.SafeZone)
+}
+
+func (c *Client) handleMatch(data []byte) {
+	var state models.MatchState
+
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c.listener.OnMatchState(&state)
+}
+
+func (c *Client) handleScoreboard(data []byte) {
+	var scoreboard []*models.Stats
+
+	err := json.Unmarshal(data, &scoreboard)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c.listener.OnScoreboard(scoreboard)
+}
+
+func (c *Client) handleRemoveModel(data []byte) {
+	var id string
+
+	err := json.Unmarshal(data, &id)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c.world.RemoveModel(id)
+}
+
+func (c *Client) handleCollide(data []byte) {
+	var collision models.Collision
+
+	err := json.Unmarshal(data, &collision)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c.listener.OnPlayersCollide(&collision)
+}
+
+func (c *Client) handleDeath(data []byte) {
+	var event models.DeathEvent
+
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if p := c.world.GetPlayer(event.Victim); p != nil {
+		p.Kill(&event)
+	}
+}
+
+func (c *Client) handleDamage(data []byte) {
+	var event models.DamageEvent
+
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if p := c.world.GetPlayer(event.Victim); p != nil {
+		p.ApplyDamage(&event)
+	}
+}
+
+func (c *Client) handleHealth(data []byte) {
+	var event models.HealthEvent
+
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if p := c.world.GetPlayer(event.Player); p != nil {
+		p.SetHP(event.HP)
+	}
+}
+
+func (c *Client) handleRespawn(data []byte) {
+	var player models.Player
+
+	err := json.Unmarshal(data, &player)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if player.Position == nil {
+		fmt.Println("player position is null")
+		return
+	}
+	if p := c.world.GetPlayer(player.GetID()); p != nil {
+		p.Respawn(*player.Position)
+	}
+}
+
+func (c *Client) handleAddPlayer(data []byte) {
+	var player models.Player
+
+	err := json.Unmarshal(data, &player)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if player.Position == nil {
+		fmt.Println("player position is null")
+		return
+	}
+	newPlayer := models.NewPlayer(player.GetID(), c.world, player.Name, *player.Position)
+
+	c.world.AddPlayer(newPlayer)
+}
+
+func (c *Client) handleExit(data []byte) {
+	var player models.Player
+
+	err := json.Unmarshal(data, &player)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if player.Position == nil {
+		fmt.Println("player position is null")
+		return
+	}
+
+	c.world.RemovePlayer(&player)
+}
+
+func (c *Client) handleYou(data []byte) {
+	var player models.Player
+
+	err := json.Unmarshal(data, &player)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if player.Position == nil {
+		fmt.Println("player position is null")
+		return
+	}
+	newPlayer := models.NewPlayer(player.GetID(), c.world, player.Name, *player.Position)
+
+	c.world.AddPlayer(newPlayer)
+}
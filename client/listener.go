@@ -0,0 +1,45 @@
+package client
+
+import (
+	"github.com/lambher/video-game/models"
+)
+
+// Listener receives the world events of the client plus the server messages
+// that do not change the world model.
+type Listener interface {
+	models.EventListener
+	OnMap(m *models.Map)
+	OnSafeZone(zone *models.SafeZone)
+	OnWave(state *models.WaveState)
+	OnScoreboard(scoreboard []*models.Stats)
+}
+
+// NopListener ignores every event. Embed it to only implement the callbacks
+// you need.
+type NopListener struct{}
+
+func (NopListener) OnAddPlayer(player *models.Player)                              {}
+func (NopListener) OnPlayerDamage(event *models.DamageEvent)                       {}
+func (NopListener) OnPlayerHealth(event *models.HealthEvent)                       {}
+func (NopListener) OnPlayersCollide(collision *models.Collision)                   {}
+func (NopListener) OnPlayerDeath(event *models.DeathEvent)                         {}
+func (NopListener) OnPlayerRespawn(player *models.Player)                          {}
+func (NopListener) OnWeaponState(player *models.Player, state *models.WeaponState) {}
+func (NopListener) OnAddBullet(bullet *models.Bullet)                              {}
+func (NopListener) OnAddMissile(missile *models.Missile)                           {}
+func (NopListener) OnHitscan(event *models.HitscanEvent)                           {}
+func (NopListener) OnExplosion(event *models.ExplosionEvent)                       {}
+func (NopListener) OnAddPickup(pickup *models.Pickup)                              {}
+func (NopListener) OnMatchState(state *models.MatchState)                          {}
+func (NopListener) OnPickup(event *models.PickupEvent)                             {}
+func (NopListener) OnAddFlag(flag *models.Flag)                                    {}
+func (NopListener) OnFlag(event *models.FlagEvent)                                 {}
+func (NopListener) OnAddZone(zone *models.Zone)                                    {}
+func (NopListener) OnAddEnemy(enemy *models.Enemy)                                 {}
+func (NopListener) OnCheckpoint(event *models.CheckpointEvent)                     {}
+func (NopListener) OnGhost(ghost *models.Ghost)                                    {}
+func (NopListener) OnRemoveModel(model models.Model)                               {}
+func (NopListener) OnMap(m *models.Map)                                            {}
+func (NopListener) OnSafeZone(zone *models.SafeZone)                               {}
+func (NopListener) OnWave(state *models.WaveState)                                 {}
+func (NopListener) OnScoreboard(scoreboard []*models.Stats)                        {}
@@ -4,8 +4,106 @@ import "time"
 
 const TickTimeClient = time.Millisecond * 50
 const TickTimeServer = time.Millisecond * 15
+const TickTimeWorld = time.Millisecond * 16
 const Port = 8888
+const MaxPacketSize = 16384
 
 //const Host = "5.39.93.173"
 
 const Host = "127.0.0.1"
+
+// Ship-to-ship collisions
+const ShipRestitution = 0.8
+const RamDamage = true
+const RamDamageMinSpeed = 0.15
+const RamDamageFactor = 100
+
+// Maps
+const MapsDir = "./assets/maps/"
+const DefaultMap = "arena"
+
+// Respawn
+const RespawnDelay = time.Second * 3
+const SpawnInvulnerability = time.Second * 2
+
+// Weapons
+const WeaponsFile = "./assets/weapons.json"
+
+// Anti-cheat
+const RejectedShotsWarning = 50
+
+// Pickups
+const PickupRespawnTime = time.Second * 20
+const RandomPickups = 4
+const HealthPickup = 50
+const ShieldPickup = 50
+const ShieldDuration = time.Second * 20
+const SpeedBoost = 1.6
+const SpeedBoostDuration = time.Second * 10
+const DamageAmplifier = 2
+const DamageAmplifierDuration = time.Second * 10
+const CloakDuration = time.Second * 15
+
+// Scoreboard
+const ScoreboardInterval = time.Second
+
+// Match
+const MinPlayers = 2
+const CountdownTime = time.Second * 5
+const TimeLimit = time.Minute * 10
+const ScoreLimit = 20
+const OvertimeLimit = time.Minute * 2
+const IntermissionTime = time.Second * 10
+
+// Teams
+const DefaultMode = "ffa"
+const FriendlyFire = "off"
+
+// Capture the flag
+const FlagReturnTime = time.Second * 30
+
+// King of the hill
+const ZoneCaptureTime = time.Second * 5
+const ZoneScoreInterval = time.Second
+const ZoneRotationTime = time.Minute
+
+// Last man standing
+const SafeZoneShrinkDelay = time.Second * 30
+const SafeZoneShrinkTime = time.Minute * 3
+const SafeZoneMinRadius = 10
+const SafeZoneDamage = 10
+
+// Survival
+const WaveBreak = time.Second * 10
+const WaveEnemies = 4
+const WaveEnemiesStep = 2
+const WaveToughness = 0.25
+const SurvivalWaves = 0
+const EnemyHP = 30
+const EnemyDamage = 10
+const EnemySpeed = 0.06
+const EnemyAttackCooldown = time.Second
+
+// Race
+const GhostInterval = time.Millisecond * 250
+const RaceLaps = 3
+
+// Mutators
+const LowGravityInertia = 0.97
+const HighKnockback = 2
+const VampirismRatio = 0.5
+
+// Bots
+const BotCount = 0
+const BotDifficulty = "normal"
+const BotSightRange = 150
+const BotTurnSpeed = 0.05
+const BotRetreatHP = 30
+const BotFillInterval = time.Second
+
+// Navigation
+const NavSpacing = 10
+const NavClearance = 1.5
+
+// Server
+const CommandQueueSize = 1024
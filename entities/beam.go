@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+type Beam struct {
+	geometry *geometry.Geometry
+	Lines    *graphic.Lines
+}
+
+func NewBeam(from, to *math32.Vector3, color *math32.Color) *Beam {
+	var beam Beam
+
+	positions := math32.NewArrayF32(0, 6)
+	positions.AppendVector3(from, to)
+	colors := math32.NewArrayF32(0, 6)
+	colors.AppendColor(color, color)
+
+	beam.geometry = geometry.NewGeometry()
+	beam.geometry.AddVBO(gls.NewVBO(positions).AddAttrib(gls.VertexPosition))
+	beam.geometry.AddVBO(gls.NewVBO(colors).AddAttrib(gls.VertexColor))
+	beam.Lines = graphic.NewLines(beam.geometry, material.NewBasic())
+
+	return &beam
+}
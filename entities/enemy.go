@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+type Enemy struct {
+	model    *models.Enemy
+	hp       int
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewEnemy(model *models.Enemy) *Enemy {
+	var enemy Enemy
+
+	enemy.material = material.NewStandard(math32.NewColor("DarkOliveGreen"))
+	enemy.geometry = geometry.NewSphere(1, 16, 8)
+	enemy.model = model
+	enemy.hp = model.HP
+	enemy.Mesh = graphic.NewMesh(enemy.geometry, enemy.material)
+	enemy.Mesh.SetPositionVec(model.Position)
+
+	m := material.NewStandard(math32.NewColor("Yellow"))
+	m.SetEmissiveColor(math32.NewColor("Yellow"))
+	eye := graphic.NewMesh(geometry.NewCube(0.4), m)
+	eye.SetPosition(0, 0, -1)
+	enemy.Mesh.Add(eye)
+
+	return &enemy
+}
+
+// Update flashes the enemy when its health drops since enemies do not get
+// damage events of their own.
+func (e *Enemy) Update() {
+	e.Mesh.SetPositionVec(e.model.Position)
+	e.Mesh.LookAt(e.model.Position.Clone().Add(e.model.Direction), math32.NewVector3(0, 1, 0))
+	if e.model.HP < e.hp {
+		e.Hit()
+	}
+	e.hp = e.model.HP
+}
+
+func (e *Enemy) Hit() {
+	go func() {
+		e.material.SetEmissiveColor(math32.NewColor("White"))
+		time.Sleep(time.Millisecond * 100)
+		e.material.SetEmissiveColor(math32.NewColor("Black"))
+	}()
+}
+
+func (e Enemy) GetMesh() *graphic.Mesh {
+	return e.Mesh
+}
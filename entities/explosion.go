@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+const explosionDuration = time.Millisecond * 400
+
+type Explosion struct {
+	event    *models.ExplosionEvent
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewExplosion(event *models.ExplosionEvent) *Explosion {
+	var explosion Explosion
+
+	explosion.material = material.NewStandard(math32.NewColor("Orange"))
+	explosion.material.SetEmissiveColor(math32.NewColor("OrangeRed"))
+	explosion.material.SetTransparent(true)
+	explosion.geometry = geometry.NewSphere(1, 24, 24)
+	explosion.event = event
+	explosion.Mesh = graphic.NewMesh(explosion.geometry, explosion.material)
+	explosion.Mesh.SetPositionVec(event.Position)
+	explosion.Mesh.SetScale(0.1, 0.1, 0.1)
+
+	return &explosion
+}
+
+// Play grows the fireball up to the explosion radius while fading it out,
+// then calls done.
+func (e *Explosion) Play(done func()) {
+	go func() {
+		start := time.Now()
+		for range time.Tick(time.Millisecond * 20) {
+			progress := float32(time.Since(start)) / float32(explosionDuration)
+			if progress >= 1 {
+				done()
+				return
+			}
+			scale := e.event.Radius * progress
+			e.Mesh.SetScale(scale, scale, scale)
+			e.material.SetOpacity(1 - progress)
+		}
+	}()
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+type Flag struct {
+	model    *models.Flag
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewFlag(model *models.Flag) *Flag {
+	var flag Flag
+
+	color := teamColor(model.Team)
+	flag.material = material.NewStandard(math32.NewColor(color))
+	flag.material.SetEmissiveColor(math32.NewColor(color).MultiplyScalar(0.5))
+	flag.geometry = geometry.NewBox(1.5, 1, 0.1)
+	flag.model = model
+	flag.Mesh = graphic.NewMesh(flag.geometry, flag.material)
+	flag.Mesh.SetPositionVec(model.Position)
+
+	pole := graphic.NewMesh(geometry.NewCylinder(0.05, 3, 8, 1, true, true), material.NewStandard(math32.NewColor("White")))
+	pole.SetPosition(-0.75, -1, 0)
+	flag.Mesh.Add(pole)
+
+	return &flag
+}
+
+// Update keeps the flag above its carrier so it does not hide inside the
+// ship.
+func (f *Flag) Update() {
+	position := f.model.Position.Clone()
+	if f.model.State == models.FlagCarried {
+		position.Y += 1.5
+	}
+	f.Mesh.SetPositionVec(position)
+}
+
+func (f Flag) GetMesh() *graphic.Mesh {
+	return f.Mesh
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+type Gate struct {
+	model    *models.Gate
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewGate(model *models.Gate) *Gate {
+	var gate Gate
+
+	gate.material = material.NewStandard(math32.NewColor("Silver"))
+	gate.geometry = geometry.NewTorus(float64(model.Radius), 0.3, 16, 48, 2*math32.Pi)
+	gate.model = model
+	gate.Mesh = graphic.NewMesh(gate.geometry, gate.material)
+	gate.Mesh.SetPositionVec(model.Position)
+	// The torus lies in the XY plane, so its axis is Z.
+	gate.Mesh.SetRotationQuat(math32.NewQuaternion(0, 0, 0, 1).SetFromUnitVectors(
+		math32.NewVector3(0, 0, 1),
+		model.Normal.Clone().Normalize(),
+	))
+
+	return &gate
+}
+
+// SetNext highlights the gate the player has to go through.
+func (g *Gate) SetNext(next bool) {
+	if next {
+		g.material.SetEmissiveColor(math32.NewColor("Lime"))
+	} else {
+		g.material.SetEmissiveColor(math32.NewColor("Black"))
+	}
+}
+
+func (g *Gate) Update() {
+}
+
+func (g Gate) GetMesh() *graphic.Mesh {
+	return g.Mesh
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+// Ghost replays the best lap of the server alongside the player, starting
+// over whenever the player starts a lap.
+type Ghost struct {
+	model    *models.Ghost
+	start    time.Time
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewGhost(model *models.Ghost) *Ghost {
+	var ghost Ghost
+
+	ghost.material = material.NewStandard(math32.NewColor("White"))
+	ghost.material.SetTransparent(true)
+	ghost.material.SetOpacity(0.3)
+	ghost.geometry = geometry.NewSphere(1, 32, 32)
+	ghost.model = model
+	ghost.Mesh = graphic.NewMesh(ghost.geometry, ghost.material)
+	ghost.Mesh.SetVisible(false)
+
+	return &ghost
+}
+
+func (g *Ghost) Start() {
+	g.start = time.Now()
+	g.Mesh.SetVisible(true)
+}
+
+func (g *Ghost) Update() {
+	if !g.Mesh.Visible() {
+		return
+	}
+	elapsed := time.Since(g.start)
+	if elapsed > g.model.LapTime {
+		g.Mesh.SetVisible(false)
+		return
+	}
+	g.Mesh.SetPositionVec(g.model.GetPosition(elapsed))
+}
+
+func (g Ghost) GetMesh() *graphic.Mesh {
+	return g.Mesh
+}
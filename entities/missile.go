@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+const trailLength = 30
+
+type Missile struct {
+	model    *models.Missile
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+
+	trail         []math32.Vector3
+	trailGeometry *geometry.Geometry
+}
+
+func NewMissile(model *models.Missile) *Missile {
+	var missile Missile
+
+	missile.material = material.NewStandard(math32.NewColor("OrangeRed"))
+	missile.material.SetEmissiveColor(math32.NewColor("DarkOrange"))
+	missile.geometry = geometry.NewSphere(0.3, 12, 12)
+	missile.model = model
+	missile.Mesh = graphic.NewMesh(missile.geometry, missile.material)
+	missile.Mesh.SetPositionVec(model.Position)
+
+	// The trail is drawn relative to the missile, so it is rebuilt on each
+	// update from the previous positions.
+	missile.trail = make([]math32.Vector3, 0, trailLength)
+	missile.trailGeometry = geometry.NewGeometry()
+	missile.trailGeometry.AddVBO(gls.NewVBO(math32.NewArrayF32(0, trailLength*3)).AddAttrib(gls.VertexPosition))
+	trailMaterial := material.NewStandard(math32.NewColor("LightGray"))
+	missile.Mesh.Add(graphic.NewLineStrip(missile.trailGeometry, trailMaterial))
+
+	return &missile
+}
+
+func (m *Missile) Update() {
+	m.Mesh.SetPositionVec(m.model.Position)
+
+	if len(m.trail) == trailLength {
+		m.trail = m.trail[1:]
+	}
+	m.trail = append(m.trail, *m.model.Position)
+
+	positions := math32.NewArrayF32(0, len(m.trail)*3)
+	for _, point := range m.trail {
+		positions.AppendVector3(point.Clone().Sub(m.model.Position))
+	}
+	m.trailGeometry.VBO(gls.VertexPosition).SetBuffer(positions)
+}
+
+func (m Missile) GetMesh() *graphic.Mesh {
+	return m.Mesh
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+type Obstacle struct {
+	model    *models.Obstacle
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewObstacle(model *models.Obstacle) *Obstacle {
+	var obstacle Obstacle
+
+	color := model.Color
+	if color == "" {
+		color = "Gray"
+	}
+	obstacle.material = material.NewStandard(math32.NewColor(color))
+	switch model.Kind {
+	case models.ObstacleBox:
+		obstacle.geometry = geometry.NewBox(model.Size.X, model.Size.Y, model.Size.Z)
+	case models.ObstacleSphere:
+		obstacle.geometry = geometry.NewSphere(float64(model.Radius), 32, 32)
+	default:
+		obstacle.geometry = newConvexGeometry(model.Vertices, model.Faces)
+	}
+	obstacle.model = model
+	obstacle.Mesh = graphic.NewMesh(obstacle.geometry, obstacle.material)
+	obstacle.Mesh.SetPositionVec(model.Position)
+
+	return &obstacle
+}
+
+// newConvexGeometry builds a flat shaded geometry, so every face gets its
+// own vertices and normal.
+func newConvexGeometry(vertices []math32.Vector3, faces [][3]int) *geometry.Geometry {
+	geom := geometry.NewGeometry()
+	positions := math32.NewArrayF32(0, len(faces)*9)
+	normals := math32.NewArrayF32(0, len(faces)*9)
+	indices := math32.NewArrayU32(0, len(faces)*3)
+
+	for i, face := range faces {
+		a, b, c := vertices[face[0]], vertices[face[1]], vertices[face[2]]
+		normal := c.Clone().Sub(&b).Cross(a.Clone().Sub(&b)).Normalize()
+		for _, vertex := range []math32.Vector3{a, b, c} {
+			positions.AppendVector3(&vertex)
+			normals.AppendVector3(normal)
+		}
+		indices.Append(uint32(i*3), uint32(i*3+1), uint32(i*3+2))
+	}
+
+	geom.SetIndices(indices)
+	geom.AddVBO(gls.NewVBO(positions).AddAttrib(gls.VertexPosition))
+	geom.AddVBO(gls.NewVBO(normals).AddAttrib(gls.VertexNormal))
+
+	return geom
+}
+
+func (o *Obstacle) Update() {
+}
+
+func (o Obstacle) GetMesh() *graphic.Mesh {
+	return o.Mesh
+}
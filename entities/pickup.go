@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+var pickupColors = map[string]string{
+	models.PickupHealth: "LimeGreen",
+	models.PickupAmmo:   "Gold",
+	models.PickupShield: "DeepSkyBlue",
+	models.PickupSpeed:  "White",
+	models.PickupDamage: "Magenta",
+	models.PickupCloak:  "SlateGray",
+}
+
+type Pickup struct {
+	model    *models.Pickup
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewPickup(model *models.Pickup) *Pickup {
+	var pickup Pickup
+
+	color, ok := pickupColors[model.Kind]
+	if !ok {
+		color = "Gray"
+	}
+	pickup.material = material.NewStandard(math32.NewColor(color))
+	pickup.material.SetEmissiveColor(math32.NewColor(color).MultiplyScalar(0.3))
+	pickup.geometry = geometry.NewCube(0.8)
+	pickup.model = model
+	pickup.Mesh = graphic.NewMesh(pickup.geometry, pickup.material)
+	pickup.Mesh.SetPositionVec(model.Position)
+	pickup.Mesh.SetVisible(model.Active)
+
+	return &pickup
+}
+
+func (p *Pickup) Update() {
+	p.Mesh.SetVisible(p.model.Active)
+	p.Mesh.RotateY(0.02)
+}
+
+func (p Pickup) GetMesh() *graphic.Mesh {
+	return p.Mesh
+}
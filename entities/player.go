@@ -7,11 +7,14 @@ import (
 	"github.com/g3n/engine/graphic"
 	"github.com/g3n/engine/material"
 	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
 	"github.com/lambher/video-game/models"
 )
 
 type Player struct {
 	model    *models.Player
+	team     string
+	color    string
 	material *material.Standard
 	geometry *geometry.Geometry
 	Mesh     *graphic.Mesh
@@ -20,7 +23,9 @@ type Player struct {
 func NewPlayer(model *models.Player) *Player {
 	var player Player
 
-	player.material = material.NewStandard(math32.NewColor("DarkBlue"))
+	player.team = model.Team
+	player.color = teamColor(model.Team)
+	player.material = material.NewStandard(math32.NewColor(player.color))
 	player.geometry = geometry.NewSphere(1, 200, 200)
 	player.model = model
 	player.Mesh = graphic.NewMesh(player.geometry, player.material)
@@ -35,31 +40,80 @@ func NewPlayer(model *models.Player) *Player {
 	return &player
 }
 
+// teamColor returns the body color of a team member, players without a team
+// keep the historical blue.
+func teamColor(team string) string {
+	switch team {
+	case models.TeamRed:
+		return "Red"
+	case models.TeamBlue:
+		return "RoyalBlue"
+	}
+	return "DarkBlue"
+}
+
 func (p *Player) Update() {
+	if p.team != p.model.Team {
+		p.team = p.model.Team
+		p.color = teamColor(p.team)
+		p.material.SetColor(math32.NewColor(p.color))
+	}
 	p.Mesh.SetPositionVec(p.model.Position)
 	p.Mesh.LookAt(p.model.GetLookAt(), p.model.Up)
+	if p.model.HasEffect(models.PickupCloak) {
+		p.material.SetTransparent(true)
+		p.material.SetOpacity(0.05)
+	} else {
+		p.material.SetTransparent(false)
+		p.material.SetOpacity(1)
+	}
 }
 
 func (p Player) GetMesh() *graphic.Mesh {
 	return p.Mesh
 }
 
+const hitColor = "White"
+
 func (p *Player) Hit() {
 	go func() {
 		flag := true
 		count := 0
-		p.material.SetColor(math32.NewColor("DarkRed"))
+		p.material.SetColor(math32.NewColor(hitColor))
 		for range time.Tick(time.Millisecond * 100) {
 			if count >= 5 {
 				return
 			}
 			count++
 			if flag {
-				p.material.SetColor(math32.NewColor("DarkBlue"))
+				p.material.SetColor(math32.NewColor(p.color))
 			} else {
-				p.material.SetColor(math32.NewColor("DarkRed"))
+				p.material.SetColor(math32.NewColor(hitColor))
 			}
 			flag = !flag
 		}
 	}()
 }
+
+func (p *Player) Bump() {
+	go func() {
+		p.material.SetEmissiveColor(math32.NewColor("White"))
+		time.Sleep(time.Millisecond * 150)
+		p.material.SetEmissiveColor(math32.NewColor("Black"))
+	}()
+}
+
+func (p *Player) Spawn() {
+	go func() {
+		visible := true
+		deadline := time.Now().Add(conf.SpawnInvulnerability)
+		for range time.Tick(time.Millisecond * 100) {
+			if time.Now().After(deadline) {
+				p.Mesh.SetVisible(true)
+				return
+			}
+			visible = !visible
+			p.Mesh.SetVisible(visible)
+		}
+	}()
+}
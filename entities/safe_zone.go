@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+type SafeZone struct {
+	model    *models.SafeZone
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewSafeZone(model *models.SafeZone) *SafeZone {
+	var zone SafeZone
+
+	zone.material = material.NewStandard(math32.NewColor("OrangeRed"))
+	zone.material.SetTransparent(true)
+	zone.material.SetOpacity(0.2)
+	zone.material.SetSide(material.SideDouble)
+	zone.geometry = geometry.NewSphere(1, 64, 64)
+	zone.model = model
+	zone.Mesh = graphic.NewMesh(zone.geometry, zone.material)
+	zone.Update()
+
+	return &zone
+}
+
+// Update scales the unit sphere to the current radius of the zone.
+func (z *SafeZone) Update() {
+	z.Mesh.SetPositionVec(z.model.Center)
+	z.Mesh.SetScale(z.model.Radius, z.model.Radius, z.model.Radius)
+}
+
+func (z SafeZone) GetMesh() *graphic.Mesh {
+	return z.Mesh
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/models"
+)
+
+type Zone struct {
+	model    *models.Zone
+	owner    string
+	material *material.Standard
+	geometry *geometry.Geometry
+	Mesh     *graphic.Mesh
+}
+
+func NewZone(model *models.Zone) *Zone {
+	var zone Zone
+
+	zone.material = material.NewStandard(math32.NewColor("White"))
+	zone.material.SetTransparent(true)
+	zone.material.SetOpacity(0.15)
+	zone.material.SetSide(material.SideDouble)
+	zone.geometry = geometry.NewSphere(float64(model.Radius), 32, 32)
+	zone.model = model
+	zone.Mesh = graphic.NewMesh(zone.geometry, zone.material)
+	zone.Mesh.SetPositionVec(model.Position)
+	zone.Mesh.SetVisible(model.Active)
+
+	return &zone
+}
+
+// Update colors the zone by team owner, white when neutral or held by a
+// single player.
+func (z *Zone) Update() {
+	z.Mesh.SetVisible(z.model.Active)
+	if z.owner == z.model.Owner {
+		return
+	}
+	z.owner = z.model.Owner
+	color := "White"
+	if z.owner == models.TeamRed || z.owner == models.TeamBlue {
+		color = teamColor(z.owner)
+	} else if z.owner != "" {
+		color = "Gold"
+	}
+	z.material.SetColor(math32.NewColor(color))
+}
+
+func (z Zone) GetMesh() *graphic.Mesh {
+	return z.Mesh
+}
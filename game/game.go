@@ -1,16 +1,13 @@
 package game
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
-	"net"
 	"os"
 	"runtime"
 	"strconv"
-	"strings"
 	"time"
 
+	"github.com/lambher/video-game/client"
 	"github.com/lambher/video-game/conf"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -27,10 +24,15 @@ import (
 	"github.com/g3n/engine/gui"
 	"github.com/g3n/engine/light"
 	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/renderer"
 	"github.com/g3n/engine/util/helper"
 	"github.com/g3n/engine/window"
 )
 
+// safeZoneEntity is the entities key of the last man standing safe zone,
+// which is not a model and has no ID of its own.
+const safeZoneEntity = "safe_zone"
+
 type Game struct {
 	world         *models.World
 	app           *app.Application
@@ -42,8 +44,10 @@ type Game struct {
 	mousePosition *math32.Vector2
 
 	entities map[string]entities.Entity
+	gates    []*entities.Gate
+	ghost    *entities.Ghost
 
-	conn net.Conn
+	client *client.Client
 }
 
 func (g *Game) OnAddPlayer(player *models.Player) {
@@ -61,14 +65,50 @@ func (g *Game) OnAddPlayer(player *models.Player) {
 	g.Scene.Add(entity.Mesh)
 }
 
-func (g *Game) OnPlayerHit(player *models.Player) {
+func (g *Game) OnPlayerDamage(event *models.DamageEvent) {
 	if g.entities == nil {
 		g.entities = make(map[string]entities.Entity)
 	}
 
-	if p, ok := g.entities[player.GetID()].(*entities.Player); ok {
+	if p, ok := g.entities[event.Victim].(*entities.Player); ok {
 		p.Hit()
 	}
+	if g.world.Player != nil && event.Victim == g.world.Player.GetID() {
+		g.gui.Hit()
+	}
+}
+
+func (g *Game) OnPlayerHealth(event *models.HealthEvent) {}
+
+func (g *Game) OnWeaponState(player *models.Player, state *models.WeaponState) {}
+
+func (g *Game) OnPlayersCollide(collision *models.Collision) {
+	for _, id := range []string{collision.PlayerA, collision.PlayerB} {
+		if p, ok := g.entities[id].(*entities.Player); ok {
+			p.Bump()
+		}
+	}
+}
+
+func (g *Game) OnPlayerDeath(event *models.DeathEvent) {
+	g.gui.AddKill(event)
+	if g.world.Player != nil && event.Victim == g.world.Player.GetID() {
+		g.gui.ShowDeath(event.RespawnIn, event.Eliminated)
+		return
+	}
+	if p, ok := g.entities[event.Victim].(*entities.Player); ok {
+		p.GetMesh().SetVisible(false)
+	}
+}
+
+func (g *Game) OnPlayerRespawn(player *models.Player) {
+	if player == g.world.Player {
+		g.gui.HideDeath()
+		return
+	}
+	if p, ok := g.entities[player.GetID()].(*entities.Player); ok {
+		p.Spawn()
+	}
 }
 
 func PrintMemUsage() {
@@ -96,205 +136,155 @@ func (g *Game) OnAddBullet(bullet *models.Bullet) {
 	g.Scene.Add(entity.Mesh)
 }
 
-func (g *Game) OnRemoveModel(model models.Model) {
-	if entity, ok := g.entities[model.GetID()]; ok {
-		//if player, ok := entity.(*entities.Player); ok {
-		//
-		//}
-		g.Scene.Remove(entity.GetMesh())
-		delete(g.entities, model.GetID())
+func (g *Game) OnAddMissile(missile *models.Missile) {
+	if g.entities == nil {
+		g.entities = make(map[string]entities.Entity)
 	}
-}
 
-func (g *Game) AddPlayer(player *models.Player) {
-	g.world.AddPlayer(player)
-}
+	entity := entities.NewMissile(missile)
+	g.entities[missile.GetID()] = entity
 
-func NewGame(app *app.Application) *Game {
-	return &Game{
-		app: app,
-	}
+	g.Scene.Add(entity.Mesh)
 }
 
-func (g *Game) connect() {
-	p := make([]byte, 2048)
-	var err error
-	g.conn, err = net.Dial("udp", conf.Host+":"+strconv.Itoa(conf.Port))
-	if err != nil {
-		fmt.Printf("Some error %v", err)
-		return
-	}
-	fmt.Fprintf(g.conn, "hello")
-	n, err := bufio.NewReader(g.conn).Read(p)
-	if err == nil {
-		g.parse(string(p[:n]))
-	} else {
-		fmt.Printf("Some error %v\n", err)
-	}
-	defer g.conn.Close()
-	g.listen()
+func (g *Game) OnHitscan(event *models.HitscanEvent) {
+	beam := entities.NewBeam(event.From, event.To, math32.NewColor("Cyan"))
+	g.Scene.Add(beam.Lines)
+	go func() {
+		time.Sleep(time.Millisecond * 200)
+		g.Scene.Remove(beam.Lines)
+	}()
 }
 
-func (g *Game) refreshPlayer() {
-	playerData, err := g.world.GetPlayerData()
+func (g *Game) OnExplosion(event *models.ExplosionEvent) {
+	explosion := entities.NewExplosion(event)
+	g.Scene.Add(explosion.Mesh)
+	explosion.Play(func() {
+		g.Scene.Remove(explosion.Mesh)
+	})
+}
 
-	data := make([]byte, 0)
+func (g *Game) OnAddPickup(pickup *models.Pickup) {
+	if g.entities == nil {
+		g.entities = make(map[string]entities.Entity)
+	}
 
-	data = append(data, []byte("refresh_player\n")...)
-	data = append(data, playerData...)
+	entity := entities.NewPickup(pickup)
+	g.entities[pickup.GetID()] = entity
 
-	_, err = fmt.Fprintf(g.conn, string(data))
-	if err != nil {
-		fmt.Println(err)
-	}
+	g.Scene.Add(entity.Mesh)
 }
 
-func (g *Game) sendMove() {
-	playerMoveData, err := g.world.GetPlayerMoveData()
+func (g *Game) OnPickup(event *models.PickupEvent) {}
 
-	data := make([]byte, 0)
+func (g *Game) OnAddFlag(flag *models.Flag) {
+	if g.entities == nil {
+		g.entities = make(map[string]entities.Entity)
+	}
 
-	data = append(data, []byte("move\n")...)
-	data = append(data, playerMoveData...)
+	entity := entities.NewFlag(flag)
+	g.entities[flag.GetID()] = entity
 
-	_, err = fmt.Fprintf(g.conn, string(data))
-	if err != nil {
-		fmt.Println(err)
-	}
+	g.Scene.Add(entity.Mesh)
 }
 
-func (g *Game) sendFire() {
-	data := make([]byte, 0)
+func (g *Game) OnAddZone(zone *models.Zone) {
+	if g.entities == nil {
+		g.entities = make(map[string]entities.Entity)
+	}
 
-	data = append(data, []byte("fire\n")...)
+	entity := entities.NewZone(zone)
+	g.entities[zone.GetID()] = entity
 
-	_, err := fmt.Fprintf(g.conn, string(data))
-	if err != nil {
-		fmt.Println(err)
-	}
+	g.Scene.Add(entity.Mesh)
 }
 
-func (g *Game) listen() {
-	for {
-		p := make([]byte, 2048)
-		n, err := bufio.NewReader(g.conn).Read(p)
-		if err == nil {
-			g.parse(string(p[:n]))
-		} else {
-			fmt.Printf("Some error %v\n", err)
-		}
+func (g *Game) OnAddEnemy(enemy *models.Enemy) {
+	if g.entities == nil {
+		g.entities = make(map[string]entities.Entity)
 	}
-}
 
-func (g *Game) parse(message string) {
-	messages := strings.Split(message, "\n")
-	if len(messages) <= 1 {
-		return
-	}
-	switch messages[0] {
-	case "you":
-		g.handleYou([]byte(messages[1]))
-	case "add_player":
-		g.handleAddPlayer([]byte(messages[1]))
-	case "exit":
-		g.handleExit([]byte(messages[1]))
-	case "refresh_player":
-		g.handleRefreshPlayer([]byte(messages[1]))
-	case "fire":
-		g.handleFire([]byte(messages[1]))
-	}
-}
+	entity := entities.NewEnemy(enemy)
+	g.entities[enemy.GetID()] = entity
 
-func (g *Game) handleRefreshPlayer(data []byte) {
-	var player models.Player
+	g.Scene.Add(entity.Mesh)
+}
 
-	err := json.Unmarshal(data, &player)
-	if err != nil {
-		fmt.Println(err)
+func (g *Game) OnCheckpoint(event *models.CheckpointEvent) {
+	g.gui.AddCheckpoint(event)
+	if g.world.Player == nil || event.Player != g.world.Player.GetID() {
 		return
 	}
-	if player.Position == nil {
-		fmt.Println("player position is null")
-		return
+	for i, gate := range g.gates {
+		gate.SetNext(i == event.NextGate)
 	}
-	if p := g.world.GetPlayer(player.GetID()); p != nil {
-		p.Refresh(player)
+	if event.Gate == 0 && g.ghost != nil {
+		g.ghost.Start()
 	}
 }
 
-func (g *Game) handleFire(data []byte) {
-	var player models.Player
-
-	err := json.Unmarshal(data, &player)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	if player.Position == nil {
-		fmt.Println("player position is null")
-		return
-	}
-	if p := g.world.GetPlayer(player.GetID()); p != nil {
-		p.Refresh(player)
-		p.Fire()
+func (g *Game) OnGhost(ghost *models.Ghost) {
+	g.gui.AddGhost(ghost)
+	if g.ghost != nil {
+		g.Scene.Remove(g.ghost.GetMesh())
 	}
+	g.ghost = entities.NewGhost(ghost)
+	g.Scene.Add(g.ghost.GetMesh())
 }
 
-func (g *Game) handleAddPlayer(data []byte) {
-	var player models.Player
+func (g *Game) OnFlag(event *models.FlagEvent) {
+	g.gui.AddFlagEvent(event)
+}
 
-	err := json.Unmarshal(data, &player)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	if player.Position == nil {
-		fmt.Println("player position is null")
-		return
+func (g *Game) OnMatchState(state *models.MatchState) {
+	g.gui.SetMatchState(state)
+}
+
+func (g *Game) OnSafeZone(zone *models.SafeZone) {
+	if g.entities == nil {
+		g.entities = make(map[string]entities.Entity)
 	}
-	newPlayer := models.NewPlayer(player.GetID(), g.world, player.Name, *player.Position)
+	entity := entities.NewSafeZone(zone)
+	g.entities[safeZoneEntity] = entity
+	g.Scene.Add(entity.Mesh)
+}
 
-	g.AddPlayer(newPlayer)
+func (g *Game) OnWave(state *models.WaveState) {
+	g.gui.SetWave(state)
 }
 
-func (g *Game) handleExit(data []byte) {
-	var player models.Player
+func (g *Game) OnScoreboard(scoreboard []*models.Stats) {
+	g.gui.SetScoreboard(scoreboard)
+}
 
-	err := json.Unmarshal(data, &player)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	if player.Position == nil {
-		fmt.Println("player position is null")
-		return
+func (g *Game) OnRemoveModel(model models.Model) {
+	if entity, ok := g.entities[model.GetID()]; ok {
+		//if player, ok := entity.(*entities.Player); ok {
+		//
+		//}
+		g.Scene.Remove(entity.GetMesh())
+		delete(g.entities, model.GetID())
 	}
-
-	g.world.RemovePlayer(&player)
 }
 
-func (g *Game) handleYou(data []byte) {
-	var player models.Player
+func (g *Game) AddPlayer(player *models.Player) {
+	g.world.AddPlayer(player)
+}
 
-	err := json.Unmarshal(data, &player)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	if player.Position == nil {
-		fmt.Println("player position is null")
-		return
+func NewGame(app *app.Application) *Game {
+	return &Game{
+		app: app,
 	}
-	newPlayer := models.NewPlayer(player.GetID(), g.world, player.Name, *player.Position)
-
-	g.AddPlayer(newPlayer)
 }
 
 func (g *Game) Init() {
-	g.world = &models.World{}
-	g.world.SubscribeEventListener(g)
-
-	go g.connect()
+	var err error
+	g.client, err = client.NewClient(g)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	g.world = g.client.World()
 
 	g.Scene = core.NewNode()
 	gui.Manager().Set(g.Scene)
@@ -335,8 +325,13 @@ func (g *Game) Init() {
 		if editName.Text() == "" {
 			return
 		}
-		g.world.Player.Name = editName.Text()
-		g.start()
+		g.client.Do(func() {
+			if g.world.Player == nil {
+				return
+			}
+			g.world.Player.Name = editName.Text()
+			g.start()
+		})
 	})
 	exitButton.Subscribe(gui.OnClick, func(s string, i interface{}) {
 		g.app.Exit()
@@ -353,25 +348,6 @@ func (g *Game) Init() {
 	//g.Cam.SetDirectionVec(newPlayer.Direction)
 	g.Scene.Add(g.Cam)
 
-	skybox, err := graphic.NewSkybox(graphic.SkyboxData{
-		DirAndPrefix: "./assets/textures/skyboxes/lambert/",
-		Extension:    "png",
-		Suffixes: [6]string{
-			"right",
-			"left",
-			"top",
-			"bottom",
-			"front",
-			"back",
-		},
-	})
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-
-	g.Scene.Add(skybox)
-
 	//fmt.Println(glfw.CursorMode)
 
 	// Set up callback to update viewport and camera aspect ratio when the window is resized
@@ -394,17 +370,22 @@ func (g *Game) Init() {
 	//})
 	//g.scene.Add(btn)
 
-	// Create and add lights to the scene
-	g.Scene.Add(light.NewAmbient(&math32.Color{1.0, 1.0, 1.0}, 0.8))
-	pointLight := light.NewPoint(&math32.Color{1, 1, 1}, 5.0)
-	pointLight.SetPosition(1, 0, 2)
-	g.Scene.Add(pointLight)
-
 	// Create and add an axis helper to the scene
 	g.Scene.Add(helper.NewAxes(0.5))
 
 	// Set background color to gray
 	g.app.Gls().ClearColor(0.5, 0.5, 0.5, 1.0)
+
+	go g.connect()
+}
+
+func (g *Game) connect() {
+	err := g.client.Connect(conf.Host + ":" + strconv.Itoa(conf.Port))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	g.client.Listen()
 }
 
 //func (g *Game) Run() {
@@ -417,7 +398,7 @@ func (g *Game) Init() {
 //}
 
 func (g *Game) start() {
-	g.refreshPlayer()
+	g.client.SendPlayer()
 	g.Scene.Remove(g.menu)
 	g.app.IWindow.(*window.GlfwWindow).SetInputMode(glfw.CursorMode, glfw.CursorHidden)
 	g.started = true
@@ -431,6 +412,54 @@ func (g *Game) pause() {
 	g.started = false
 }
 
+// OnMap builds the scenery of the map once the client loaded it.
+func (g *Game) OnMap(m *models.Map) {
+	skybox, err := graphic.NewSkybox(graphic.SkyboxData{
+		DirAndPrefix: m.Skybox,
+		Extension:    "png",
+		Suffixes: [6]string{
+			"right",
+			"left",
+			"top",
+			"bottom",
+			"front",
+			"back",
+		},
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	g.Scene.Add(skybox)
+
+	for _, l := range m.Lights {
+		switch l.Kind {
+		case models.LightAmbient:
+			g.Scene.Add(light.NewAmbient(l.Color, l.Intensity))
+		case models.LightPoint:
+			pointLight := light.NewPoint(l.Color, l.Intensity)
+			pointLight.SetPositionVec(l.Position)
+			g.Scene.Add(pointLight)
+		case models.LightDirectional:
+			directionalLight := light.NewDirectional(l.Color, l.Intensity)
+			directionalLight.SetPositionVec(l.Position)
+			g.Scene.Add(directionalLight)
+		}
+	}
+
+	for _, obstacle := range m.Obstacles {
+		g.Scene.Add(entities.NewObstacle(obstacle).GetMesh())
+	}
+
+	for i, gate := range m.Gates {
+		entity := entities.NewGate(gate)
+		entity.SetNext(i == 0)
+		g.gates = append(g.gates, entity)
+		g.Scene.Add(entity.GetMesh())
+	}
+}
+
 func (g *Game) initGUI() {
 	width, height := g.app.GetSize()
 	g.gui = gui2.NewGUI(g.world, width, height)
@@ -443,42 +472,59 @@ func (g *Game) listenEvent() {
 			return
 		}
 		if keyEvent, ok := ev.(*window.KeyEvent); ok {
-			if keyEvent.Key == window.KeyW {
-				g.world.Player.MoveForward(true)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyS {
-				g.world.Player.MoveBackward(true)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyD {
-				g.world.Player.MoveRight(true)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyA {
-				g.world.Player.MoveLeft(true)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyLeft {
-				g.world.Player.TurnLeft(true, 0.5)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyRight {
-				g.world.Player.TurnRight(true, 0.5)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyUp {
-				g.world.Player.TurnUp(true, 0.5)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyDown {
-				g.world.Player.TurnDown(true, 0.5)
-				g.sendMove()
-			}
-
-			if keyEvent.Key == window.KeyEscape {
-				g.pause()
-			}
+			g.client.Do(func() {
+				if keyEvent.Key == window.KeyW {
+					g.world.Player.MoveForward(true)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyS {
+					g.world.Player.MoveBackward(true)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyD {
+					g.world.Player.MoveRight(true)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyA {
+					g.world.Player.MoveLeft(true)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyLeft {
+					g.world.Player.TurnLeft(true, 0.5)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyRight {
+					g.world.Player.TurnRight(true, 0.5)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyUp {
+					g.world.Player.TurnUp(true, 0.5)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyDown {
+					g.world.Player.TurnDown(true, 0.5)
+					g.client.SendMove()
+				}
+
+				if keyEvent.Key == window.KeyR {
+					g.client.SendReload()
+				}
+
+				if keyEvent.Key == window.KeyTab {
+					g.gui.ShowScoreboard(true)
+				}
+
+				if keyEvent.Key >= window.Key1 && keyEvent.Key <= window.Key9 {
+					index := int(keyEvent.Key - window.Key1)
+					if index < len(g.world.Weapons) {
+						g.client.SendWeapon(g.world.Weapons[index].Name)
+					}
+				}
+
+				if keyEvent.Key == window.KeyEscape {
+					g.pause()
+				}
+			})
 		}
 	})
 
@@ -487,38 +533,43 @@ func (g *Game) listenEvent() {
 			return
 		}
 		if keyEvent, ok := ev.(*window.KeyEvent); ok {
-			if keyEvent.Key == window.KeyW {
-				g.world.Player.MoveForward(false)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyS {
-				g.world.Player.MoveBackward(false)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyD {
-				g.world.Player.MoveRight(false)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyA {
-				g.world.Player.MoveLeft(false)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyLeft {
-				g.world.Player.TurnLeft(false, 0.01)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyRight {
-				g.world.Player.TurnRight(false, 0.01)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyUp {
-				g.world.Player.TurnUp(false, 0.01)
-				g.sendMove()
-			}
-			if keyEvent.Key == window.KeyDown {
-				g.world.Player.TurnDown(false, 0.01)
-				g.sendMove()
-			}
+			g.client.Do(func() {
+				if keyEvent.Key == window.KeyW {
+					g.world.Player.MoveForward(false)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyS {
+					g.world.Player.MoveBackward(false)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyD {
+					g.world.Player.MoveRight(false)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyA {
+					g.world.Player.MoveLeft(false)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyLeft {
+					g.world.Player.TurnLeft(false, 0.01)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyRight {
+					g.world.Player.TurnRight(false, 0.01)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyUp {
+					g.world.Player.TurnUp(false, 0.01)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyDown {
+					g.world.Player.TurnDown(false, 0.01)
+					g.client.SendMove()
+				}
+				if keyEvent.Key == window.KeyTab {
+					g.gui.ShowScoreboard(false)
+				}
+			})
 		}
 	})
 
@@ -527,9 +578,11 @@ func (g *Game) listenEvent() {
 			return
 		}
 		if mouseEvent, ok := ev.(*window.MouseEvent); ok {
-			if mouseEvent.Button == window.MouseButton1 {
-				g.sendFire()
-			}
+			g.client.Do(func() {
+				if mouseEvent.Button == window.MouseButton1 {
+					g.client.SendFire()
+				}
+			})
 		}
 	})
 
@@ -541,43 +594,57 @@ func (g *Game) listenEvent() {
 			return
 		}
 		if cursorEvent, ok := ev.(*window.CursorEvent); ok {
-			g.world.Player.TurnLeft(false, 1)
-			g.world.Player.TurnRight(false, 1)
-			g.world.Player.TurnDown(false, 1)
-			g.world.Player.TurnUp(false, 1)
-
-			x := -g.mousePosition.X + cursorEvent.Xpos
-			y := -g.mousePosition.Y + cursorEvent.Ypos
-
-			x *= 0.002
-			y *= 0.002
-
-			if x < 0 {
-				g.world.Player.TurnLeft(true, -x)
-			}
-			if x > 0 {
-				g.world.Player.TurnRight(true, x)
-			}
-			if y < 0 {
-				g.world.Player.TurnUp(true, -y)
-			}
-			if y > 0 {
-				g.world.Player.TurnDown(true, y)
-			}
-			g.sendMove()
-
+			g.client.Do(func() {
+				g.world.Player.TurnLeft(false, 1)
+				g.world.Player.TurnRight(false, 1)
+				g.world.Player.TurnDown(false, 1)
+				g.world.Player.TurnUp(false, 1)
+
+				x := -g.mousePosition.X + cursorEvent.Xpos
+				y := -g.mousePosition.Y + cursorEvent.Ypos
+
+				x *= 0.002
+				y *= 0.002
+
+				if x < 0 {
+					g.world.Player.TurnLeft(true, -x)
+				}
+				if x > 0 {
+					g.world.Player.TurnRight(true, x)
+				}
+				if y < 0 {
+					g.world.Player.TurnUp(true, -y)
+				}
+				if y > 0 {
+					g.world.Player.TurnDown(true, y)
+				}
+				g.client.SendMove()
+			})
 		}
 	})
 }
 
 func (g *Game) SendExit() {
-	_, err := fmt.Fprintf(g.conn, "exit")
-	if err != nil {
-		fmt.Println(err)
-	}
+	g.client.SendExit()
 }
 
+// Update moves the world and the entities forward. The client updates the
+// same world from the network goroutine, so it all runs inside Do.
 func (g *Game) Update(deltaTime time.Duration) {
+	g.client.Do(func() {
+		g.update(deltaTime)
+	})
+}
+
+// Render draws the scene inside Do, as the listener callbacks add and remove
+// meshes from the network goroutine.
+func (g *Game) Render(renderer *renderer.Renderer) {
+	g.client.Do(func() {
+		renderer.Render(g.Scene, g.Cam)
+	})
+}
+
+func (g *Game) update(deltaTime time.Duration) {
 	if g.world.Player == nil {
 		return
 	}
@@ -585,7 +652,7 @@ func (g *Game) Update(deltaTime time.Duration) {
 	//g.axes.SetDirectionVec(g.world.Player.Direction)
 	g.gui.Update()
 	//g.world.Player.Update(deltaTime)
-	g.world.UpdatePositions(deltaTime)
+	g.client.Update(deltaTime)
 	g.Cam.SetPositionVec(g.world.Player.Position)
 	//g.cam.SetDirectionVec(g.world.Player.Direction)
 	g.Cam.LookAt(g.world.Player.Direction.Clone().Add(g.world.Player.Position), g.world.Player.Up)
@@ -599,4 +666,7 @@ func (g *Game) Update(deltaTime time.Duration) {
 	for _, entity := range g.entities {
 		entity.Update()
 	}
+	if g.ghost != nil {
+		g.ghost.Update()
+	}
 }
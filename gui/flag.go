@@ -0,0 +1,64 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/text"
+	"github.com/lambher/video-game/models"
+)
+
+func (g *GUI) initFlags(font *text.Font, width, height int) {
+	g.flagsLabel = gui.NewLabel("")
+	g.flagsLabel.SetFontSize(18)
+	g.flagsLabel.SetFont(font)
+	g.flagsLabel.SetPosition(float32(width)/2-150, 40)
+
+	g.Node.Add(g.flagsLabel)
+}
+
+// AddFlagEvent reports flag pickups, drops, returns and captures in the kill
+// feed.
+func (g *GUI) AddFlagEvent(event *models.FlagEvent) {
+	team := strings.ToUpper(event.Team)
+	switch event.Action {
+	case models.FlagActionPickup:
+		g.addFeed(fmt.Sprintf("%s took the %s flag", event.PlayerName, team))
+	case models.FlagActionDrop:
+		g.addFeed(fmt.Sprintf("%s flag dropped", team))
+	case models.FlagActionReturn:
+		if event.PlayerName != "" {
+			g.addFeed(fmt.Sprintf("%s returned the %s flag", event.PlayerName, team))
+		} else {
+			g.addFeed(fmt.Sprintf("%s flag returned", team))
+		}
+	case models.FlagActionCapture:
+		g.addFeed(fmt.Sprintf("%s captured the %s flag", event.PlayerName, team))
+	}
+}
+
+// updateFlags shows where each flag is.
+func (g *GUI) updateFlags() {
+	flags := g.world.GetFlags()
+	lines := make([]string, 0, len(flags))
+	for _, team := range models.Teams {
+		for _, flag := range flags {
+			if flag.Team != team {
+				continue
+			}
+			status := flag.State
+			if flag.State == models.FlagCarried {
+				status = "taken"
+				if carrier := g.world.GetPlayer(flag.Carrier); carrier != nil {
+					status = fmt.Sprintf("taken by %s", carrier.Name)
+				}
+				if flag.Carrier == g.world.Player.GetID() {
+					status = "YOU HAVE IT"
+				}
+			}
+			lines = append(lines, fmt.Sprintf("%s flag: %s", strings.ToUpper(team), status))
+		}
+	}
+	g.flagsLabel.SetText(strings.Join(lines, "   "))
+}
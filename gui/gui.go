@@ -2,20 +2,59 @@ package gui
 
 import (
 	"fmt"
+	"math"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/g3n/engine/text"
 
 	"github.com/g3n/engine/core"
 
 	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/math32"
 	"github.com/lambher/video-game/models"
 )
 
+const targetRange = 200
+
 type GUI struct {
-	hpLabel   *gui.Label
-	nameLabel *gui.Label
-	world     *models.World
+	hpLabel      *gui.Label
+	nameLabel    *gui.Label
+	deathLabel   *gui.Label
+	weaponLabel  *gui.Label
+	targetLabel  *gui.Label
+	lockLabel    *gui.Label
+	effectsLabel *gui.Label
+	world        *models.World
+	respawnAt    time.Time
+	eliminated   bool
+
+	scoreboardLabel *gui.Label
+	scoreboardHeld  bool
+	killFeedLabel   *gui.Label
+	killFeed        []kill
+	killFeedLock    sync.Mutex
+
+	matchLabel  *gui.Label
+	match       *models.MatchState
+	matchEndsAt time.Time
+	matchLock   sync.Mutex
+
+	flagsLabel *gui.Label
+	zonesLabel *gui.Label
+	safeLabel  *gui.Label
+
+	waveLabel *gui.Label
+	wave      *models.WaveState
+	waveLock  sync.Mutex
+
+	raceLabel *gui.Label
+	race      *models.CheckpointEvent
+	lapStart  time.Time
+	splitAt   time.Time
+	raceLock  sync.Mutex
 
 	*core.Node
 }
@@ -43,8 +82,48 @@ func NewGUI(world *models.World, width, height int) *GUI {
 	GUI.nameLabel.SetFont(font)
 	GUI.nameLabel.SetPosition(10, 10)
 
+	GUI.weaponLabel = gui.NewLabel("Weapon")
+	GUI.weaponLabel.SetFontSize(25)
+	GUI.weaponLabel.SetFont(font)
+	GUI.weaponLabel.SetPosition(float32(width)-450, float32(height)-100)
+
+	GUI.targetLabel = gui.NewLabel("")
+	GUI.targetLabel.SetFontSize(20)
+	GUI.targetLabel.SetFont(font)
+	GUI.targetLabel.SetPosition(float32(width)/2+20, float32(height)/2+20)
+
+	GUI.lockLabel = gui.NewLabel("MISSILE LOCK")
+	GUI.lockLabel.SetFontSize(30)
+	GUI.lockLabel.SetFont(font)
+	GUI.lockLabel.SetColor(math32.NewColor("Red"))
+	GUI.lockLabel.SetPosition(float32(width)/2-150, 100)
+	GUI.lockLabel.SetVisible(false)
+
+	GUI.effectsLabel = gui.NewLabel("")
+	GUI.effectsLabel.SetFontSize(20)
+	GUI.effectsLabel.SetFont(font)
+	GUI.effectsLabel.SetPosition(10, float32(height)-60)
+
+	GUI.deathLabel = gui.NewLabel("DESTROYED")
+	GUI.deathLabel.SetFontSize(50)
+	GUI.deathLabel.SetFont(font)
+	GUI.deathLabel.SetPosition(float32(width)/2-300, float32(height)/2)
+	GUI.deathLabel.SetVisible(false)
+
 	GUI.Node.Add(GUI.hpLabel)
 	GUI.Node.Add(GUI.nameLabel)
+	GUI.Node.Add(GUI.deathLabel)
+	GUI.Node.Add(GUI.weaponLabel)
+	GUI.Node.Add(GUI.targetLabel)
+	GUI.Node.Add(GUI.lockLabel)
+	GUI.Node.Add(GUI.effectsLabel)
+
+	GUI.initScoreboard(font, width, height)
+	GUI.initMatch(font, width, height)
+	GUI.initFlags(font, width, height)
+	GUI.initZones(font, width, height)
+	GUI.initWave(font, width, height)
+	GUI.initRace(font, width, height)
 
 	return &GUI
 }
@@ -54,5 +133,89 @@ func (g *GUI) Update() {
 		return
 	}
 	g.hpLabel.SetText(fmt.Sprintf("HP:%d", g.world.Player.GetHP()))
-	g.nameLabel.SetText(fmt.Sprintf("%s", g.world.Player.Name))
+	if g.world.Player.Team != "" {
+		g.nameLabel.SetText(fmt.Sprintf("%s [%s]", g.world.Player.Name, strings.ToUpper(g.world.Player.Team)))
+	} else {
+		g.nameLabel.SetText(fmt.Sprintf("%s", g.world.Player.Name))
+	}
+	if g.world.NoWeapons {
+		g.weaponLabel.SetText("WEAPONS DISABLED")
+	} else if state := g.world.Player.GetWeaponState(); state == nil {
+		g.weaponLabel.SetText(g.world.Player.Weapon)
+	} else if state.Reloading {
+		g.weaponLabel.SetText(fmt.Sprintf("%s RELOADING", state.Weapon))
+	} else {
+		g.weaponLabel.SetText(fmt.Sprintf("%s %d/%d", state.Weapon, state.Ammo, state.Reserve))
+	}
+	g.updateTarget()
+	g.updateEffects()
+	g.updateKillFeed()
+	g.updateMatch()
+	g.updateFlags()
+	g.updateZones()
+	g.updateSafeZone()
+	g.updateWave()
+	g.updateRace()
+	g.lockLabel.SetVisible(g.world.IsTargeted(g.world.Player))
+	if g.deathLabel.Visible() && g.eliminated {
+		g.deathLabel.SetText("ELIMINATED - waiting for the next round")
+	} else if g.deathLabel.Visible() {
+		respawnIn := time.Until(g.respawnAt).Seconds()
+		if respawnIn < 0 {
+			respawnIn = 0
+		}
+		g.deathLabel.SetText(fmt.Sprintf("DESTROYED - respawn in %.0f", math.Ceil(respawnIn)))
+	}
+}
+
+// updateTarget shows the player under the crosshair, unless it is cloaked.
+func (g *GUI) updateTarget() {
+	player := g.world.Player
+	hit := g.world.Raycast(player.Position, player.Direction, targetRange, func(model models.Model) bool {
+		return model != player
+	})
+	if hit == nil {
+		g.targetLabel.SetText("")
+		return
+	}
+	if target, ok := hit.Model.(*models.Player); ok && !target.IsCloaked() {
+		g.targetLabel.SetText(fmt.Sprintf("%s %.0fm", target.Name, hit.Distance))
+		return
+	}
+	g.targetLabel.SetText("")
+}
+
+func (g *GUI) updateEffects() {
+	effects := make([]string, 0)
+	for _, kind := range models.PickupKinds {
+		remaining, ok := g.world.Player.Effects[kind]
+		if !ok {
+			continue
+		}
+		effect := fmt.Sprintf("%s %.0fs", strings.ToUpper(kind), remaining.Seconds())
+		if kind == models.PickupShield {
+			effect = fmt.Sprintf("SHIELD %d %.0fs", g.world.Player.Shield, remaining.Seconds())
+		}
+		effects = append(effects, effect)
+	}
+	g.effectsLabel.SetText(strings.Join(effects, "  "))
+}
+
+func (g *GUI) Hit() {
+	go func() {
+		color := g.hpLabel.Color()
+		g.hpLabel.SetColor(math32.NewColor("Red"))
+		time.Sleep(time.Millisecond * 300)
+		g.hpLabel.SetColor4(&color)
+	}()
+}
+
+func (g *GUI) ShowDeath(respawnIn time.Duration, eliminated bool) {
+	g.respawnAt = time.Now().Add(respawnIn)
+	g.eliminated = eliminated
+	g.deathLabel.SetVisible(true)
+}
+
+func (g *GUI) HideDeath() {
+	g.deathLabel.SetVisible(false)
 }
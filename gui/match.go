@@ -0,0 +1,94 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/text"
+	"github.com/lambher/video-game/models"
+)
+
+func (g *GUI) initMatch(font *text.Font, width, height int) {
+	g.matchLabel = gui.NewLabel("")
+	g.matchLabel.SetFontSize(25)
+	g.matchLabel.SetFont(font)
+	g.matchLabel.SetPosition(float32(width)/2-200, 10)
+
+	g.Node.Add(g.matchLabel)
+}
+
+func (g *GUI) SetMatchState(state *models.MatchState) {
+	g.matchLock.Lock()
+	g.match = state
+	g.matchEndsAt = time.Now().Add(state.TimeLeft)
+	g.matchLock.Unlock()
+}
+
+func (g *GUI) updateMatch() {
+	g.matchLock.Lock()
+	state := g.match
+	timeLeft := time.Until(g.matchEndsAt)
+	g.matchLock.Unlock()
+
+	if state == nil {
+		return
+	}
+	if timeLeft < 0 {
+		timeLeft = 0
+	}
+	clock := fmt.Sprintf("%02d:%02d", int(timeLeft.Minutes()), int(timeLeft.Seconds())%60)
+
+	switch state.Phase {
+	case models.PhaseWarmup:
+		g.matchLabel.SetText("WARMUP - waiting for players" + formatMutators(state))
+	case models.PhaseCountdown:
+		g.matchLabel.SetText(fmt.Sprintf("STARTING IN %.0f", timeLeft.Seconds()) + formatMutators(state))
+	case models.PhaseInProgress:
+		g.matchLabel.SetText(fmt.Sprintf("%s %s", g.formatScores(state), clock))
+	case models.PhaseOvertime:
+		g.matchLabel.SetText(fmt.Sprintf("OVERTIME %s %s", g.formatScores(state), clock))
+	case models.PhaseIntermission:
+		winner := "DRAW"
+		if state.Mode == models.ModeSurvival {
+			winner = "WIPED OUT"
+		} else if state.WinnerName != "" {
+			winner = fmt.Sprintf("%s WINS", state.WinnerName)
+		}
+		g.matchLabel.SetText(fmt.Sprintf("%s - next match in %s", winner, clock))
+	}
+
+	g.scoreboardLabel.SetVisible(g.scoreboardHeld || state.Phase == models.PhaseIntermission)
+}
+
+// formatScores shows the leading score against the limit, or every team
+// score in team modes.
+func (g *GUI) formatScores(state *models.MatchState) string {
+	if len(state.Scores) == 0 {
+		return ""
+	}
+	if state.Teams {
+		scores := make([]string, 0, len(state.Scores))
+		for _, score := range state.Scores {
+			scores = append(scores, fmt.Sprintf("%s %d", score.Name, score.Score))
+		}
+		if state.ScoreLimit > 0 {
+			return fmt.Sprintf("%s /%d", strings.Join(scores, " - "), state.ScoreLimit)
+		}
+		return strings.Join(scores, " - ")
+	}
+	leader := state.Scores[0]
+	if state.ScoreLimit > 0 {
+		return fmt.Sprintf("%s %d/%d", leader.Name, leader.Score, state.ScoreLimit)
+	}
+	return fmt.Sprintf("%s %d", leader.Name, leader.Score)
+}
+
+// formatMutators lists the mutators of the match, if any.
+func formatMutators(state *models.MatchState) string {
+	if len(state.Mutators) == 0 {
+		return ""
+	}
+	return " - " + strings.ToUpper(strings.Join(state.Mutators, " + "))
+}
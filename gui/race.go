@@ -0,0 +1,78 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/text"
+	"github.com/lambher/video-game/models"
+)
+
+const splitDuration = time.Second * 3
+
+func (g *GUI) initRace(font *text.Font, width, height int) {
+	g.raceLabel = gui.NewLabel("")
+	g.raceLabel.SetFontSize(20)
+	g.raceLabel.SetFont(font)
+	g.raceLabel.SetPosition(float32(width)/2-200, 40)
+
+	g.Node.Add(g.raceLabel)
+}
+
+// AddCheckpoint keeps the lap progress of the player and reports completed
+// laps of everyone in the kill feed.
+func (g *GUI) AddCheckpoint(event *models.CheckpointEvent) {
+	if event.LapTime > 0 {
+		line := fmt.Sprintf("%s lap %s", event.PlayerName, formatLapTime(event.LapTime))
+		if event.NewBest {
+			line += " personal best"
+		}
+		g.addFeed(line)
+	}
+	if g.world.Player == nil || event.Player != g.world.Player.GetID() {
+		return
+	}
+
+	g.raceLock.Lock()
+	if g.race == nil || event.Gate == 0 {
+		g.lapStart = time.Now()
+	}
+	g.race = event
+	g.splitAt = time.Now()
+	g.raceLock.Unlock()
+}
+
+func (g *GUI) AddGhost(ghost *models.Ghost) {
+	g.addFeed(fmt.Sprintf("New best lap %s by %s", formatLapTime(ghost.LapTime), ghost.Name))
+}
+
+// updateRace shows the current lap, the running time, the best lap and, for
+// a few seconds after each gate, the split against the best lap.
+func (g *GUI) updateRace() {
+	g.raceLock.Lock()
+	event := g.race
+	lapStart := g.lapStart
+	splitAt := g.splitAt
+	g.raceLock.Unlock()
+
+	if event == nil {
+		return
+	}
+	line := fmt.Sprintf("LAP %d  GATE %d/%d  %s", event.Lap+1, event.NextGate+1, len(g.world.Gates), formatLapTime(time.Since(lapStart)))
+	if event.BestLap > 0 {
+		line += fmt.Sprintf("  BEST %s", formatLapTime(event.BestLap))
+	}
+	if time.Since(splitAt) < splitDuration {
+		if event.LapTime > 0 {
+			line += fmt.Sprintf("  LAP %s", formatLapTime(event.LapTime))
+		} else if event.Delta != 0 {
+			line += fmt.Sprintf("  %+.2f", event.Delta.Seconds())
+		}
+	}
+	g.raceLabel.SetText(line)
+}
+
+func formatLapTime(d time.Duration) string {
+	return fmt.Sprintf("%d:%05.2f", int(d.Minutes()), d.Seconds()-float64(int(d.Minutes())*60))
+}
@@ -0,0 +1,94 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/text"
+	"github.com/lambher/video-game/models"
+)
+
+const killFeedSize = 5
+const killFeedDuration = time.Second * 5
+
+type kill struct {
+	text string
+	at   time.Time
+}
+
+func (g *GUI) initScoreboard(font *text.Font, width, height int) {
+	g.scoreboardLabel = gui.NewLabel("")
+	g.scoreboardLabel.SetFontSize(20)
+	g.scoreboardLabel.SetFont(font)
+	g.scoreboardLabel.SetBgColor4(&math32.Color4{R: 0, G: 0, B: 0, A: 0.6})
+	g.scoreboardLabel.SetPosition(float32(width)/2-400, 150)
+	g.scoreboardLabel.SetVisible(false)
+
+	g.killFeedLabel = gui.NewLabel("")
+	g.killFeedLabel.SetFontSize(16)
+	g.killFeedLabel.SetFont(font)
+	g.killFeedLabel.SetPosition(float32(width)-600, 10)
+
+	g.Node.Add(g.scoreboardLabel)
+	g.Node.Add(g.killFeedLabel)
+}
+
+func (g *GUI) SetScoreboard(scoreboard []*models.Stats) {
+	lines := []string{fmt.Sprintf("%-16s %5s %5s %5s %6s %5s", "NAME", "K", "D", "A", "DMG", "ACC")}
+	for _, stats := range scoreboard {
+		lines = append(lines, fmt.Sprintf("%-16s %5d %5d %5d %6d %4.0f%%",
+			stats.Name, stats.Kills, stats.Deaths, stats.Assists, stats.DamageDealt, stats.GetAccuracy()*100))
+	}
+	g.scoreboardLabel.SetText(strings.Join(lines, "\n"))
+}
+
+func (g *GUI) ShowScoreboard(visible bool) {
+	g.scoreboardHeld = visible
+	g.scoreboardLabel.SetVisible(visible)
+}
+
+// AddKill pushes "A destroyed B with weapon" to the kill feed.
+func (g *GUI) AddKill(event *models.DeathEvent) {
+	if event.Weapon == "" {
+		// Not a kill, e.g. a player joining a round that already started.
+		return
+	}
+	line := fmt.Sprintf("%s destroyed %s with %s", event.AttackerName, event.VictimName, event.Weapon)
+	if event.Attacker == event.Victim {
+		line = fmt.Sprintf("%s destroyed themselves with %s", event.VictimName, event.Weapon)
+	} else if event.AttackerName == "" {
+		line = fmt.Sprintf("%s was destroyed by the %s", event.VictimName, event.Weapon)
+	}
+	g.addFeed(line)
+}
+
+func (g *GUI) addFeed(line string) {
+	g.killFeedLock.Lock()
+	g.killFeed = append(g.killFeed, kill{
+		text: line,
+		at:   time.Now(),
+	})
+	if len(g.killFeed) > killFeedSize {
+		g.killFeed = g.killFeed[len(g.killFeed)-killFeedSize:]
+	}
+	g.killFeedLock.Unlock()
+}
+
+func (g *GUI) updateKillFeed() {
+	g.killFeedLock.Lock()
+	lines := make([]string, 0, len(g.killFeed))
+	kills := g.killFeed[:0]
+	for _, k := range g.killFeed {
+		if time.Since(k.at) < killFeedDuration {
+			kills = append(kills, k)
+			lines = append(lines, k.text)
+		}
+	}
+	g.killFeed = kills
+	g.killFeedLock.Unlock()
+
+	g.killFeedLabel.SetText(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,44 @@
+package gui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/text"
+	"github.com/lambher/video-game/models"
+)
+
+func (g *GUI) initWave(font *text.Font, width, height int) {
+	g.waveLabel = gui.NewLabel("")
+	g.waveLabel.SetFontSize(20)
+	g.waveLabel.SetFont(font)
+	g.waveLabel.SetPosition(float32(width)/2-150, 40)
+
+	g.Node.Add(g.waveLabel)
+}
+
+func (g *GUI) SetWave(state *models.WaveState) {
+	g.waveLock.Lock()
+	g.wave = state
+	g.waveLock.Unlock()
+}
+
+// updateWave shows the survival wave progress, counting the enemies the
+// client knows about so the counter does not lag behind the kills.
+func (g *GUI) updateWave() {
+	g.waveLock.Lock()
+	state := g.wave
+	g.waveLock.Unlock()
+
+	if state == nil {
+		return
+	}
+	if state.Break {
+		nextIn := math.Ceil(state.NextIn.Seconds())
+		g.waveLabel.SetText(fmt.Sprintf("WAVE %d in %.0f", state.Wave+1, nextIn))
+		return
+	}
+	remaining := len(g.world.GetEnemies())
+	g.waveLabel.SetText(fmt.Sprintf("WAVE %d - %d/%d enemies left", state.Wave, remaining, state.Total))
+}
@@ -0,0 +1,57 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/text"
+)
+
+func (g *GUI) initZones(font *text.Font, width, height int) {
+	g.zonesLabel = gui.NewLabel("")
+	g.zonesLabel.SetFontSize(18)
+	g.zonesLabel.SetFont(font)
+	g.zonesLabel.SetPosition(float32(width)/2-150, 70)
+
+	g.safeLabel = gui.NewLabel("OUTSIDE THE SAFE ZONE")
+	g.safeLabel.SetFontSize(25)
+	g.safeLabel.SetFont(font)
+	g.safeLabel.SetColor(math32.NewColor("Red"))
+	g.safeLabel.SetPosition(float32(width)/2-180, float32(height)/2+100)
+	g.safeLabel.SetVisible(false)
+
+	g.Node.Add(g.zonesLabel)
+	g.Node.Add(g.safeLabel)
+}
+
+// updateZones shows the owner and capture progress of each active zone.
+func (g *GUI) updateZones() {
+	zones := g.world.GetZones()
+	lines := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		if !zone.Active {
+			continue
+		}
+		status := "neutral"
+		if zone.OwnerName != "" {
+			status = strings.ToUpper(zone.OwnerName)
+		}
+		if zone.Contested {
+			status += " CONTESTED"
+		} else if zone.Holder != "" && zone.Holder != zone.Owner {
+			status += fmt.Sprintf(" %.0f%%", zone.Progress*100)
+		}
+		lines = append(lines, fmt.Sprintf("ZONE %s: %s", zone.Name, status))
+	}
+	g.zonesLabel.SetText(strings.Join(lines, "   "))
+}
+
+// updateSafeZone warns the player when they are taking damage outside the
+// last man standing safe zone.
+func (g *GUI) updateSafeZone() {
+	zone := g.world.SafeZone
+	player := g.world.Player
+	g.safeLabel.SetVisible(zone != nil && !player.Dead && !zone.Contains(player.Position))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lambher/video-game/client"
+	"github.com/lambher/video-game/conf"
+	"github.com/lambher/video-game/models"
+)
+
+// loadgen joins a server with headless clients flying and firing at random,
+// to see how the server holds up with a full arena.
+func main() {
+	addr := flag.String("addr", conf.Host+":"+strconv.Itoa(conf.Port), "address of the server")
+	count := flag.Int("clients", 8, "number of clients to connect")
+	duration := flag.Duration("duration", time.Minute, "how long the clients stay connected")
+	flag.Parse()
+
+	var wg sync.WaitGroup
+	for i := 0; i < *count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			run(*addr, fmt.Sprintf("Load %d", i+1), *duration)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// deaths counts the deaths seen by a client. The count is written by the
+// Listen goroutine and read by run.
+type deaths struct {
+	client.NopListener
+	count int64
+}
+
+func (d *deaths) OnPlayerDeath(event *models.DeathEvent) {
+	atomic.AddInt64(&d.count, 1)
+}
+
+func run(addr, name string, duration time.Duration) {
+	listener := &deaths{}
+	c, err := client.NewClient(listener)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	err = c.Connect(addr)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer c.SendExit()
+	go c.Listen()
+
+	tick := time.Tick(conf.TickTimeClient)
+	timeout := time.After(duration)
+	for {
+		select {
+		case <-tick:
+			c.Do(func() {
+				player := c.Player()
+				if player == nil {
+					return
+				}
+				if player.Name != name {
+					player.Name = name
+					c.SendPlayer()
+				}
+				player.MoveForward(rand.Intn(4) != 0)
+				player.TurnLeft(true, rand.Float32()*0.1)
+				player.TurnUp(true, rand.Float32()*0.02)
+				c.SendMove()
+				if rand.Intn(5) == 0 {
+					c.SendFire()
+				}
+			})
+		case <-timeout:
+			fmt.Printf("%s saw %d deaths\n", name, atomic.LoadInt64(&listener.count))
+			return
+		}
+	}
+}
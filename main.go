@@ -22,6 +22,6 @@ func main() {
 	a.Run(func(renderer *renderer.Renderer, deltaTime time.Duration) {
 		g.Update(deltaTime)
 		a.Gls().Clear(gls.DEPTH_BUFFER_BIT | gls.STENCIL_BUFFER_BIT | gls.COLOR_BUFFER_BIT)
-		renderer.Render(g.Scene, g.Cam)
+		g.Render(renderer)
 	})
 }
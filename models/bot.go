@@ -0,0 +1,383 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+const (
+	BotPatrol  = "patrol"
+	BotChase   = "chase"
+	BotStrafe  = "strafe"
+	BotRetreat = "retreat"
+)
+
+// Difficulty tunes how fast a bot reacts to a new target and how far off
+// its aim is, in radians.
+type Difficulty struct {
+	Name         string
+	ReactionTime time.Duration
+	AimError     float32
+}
+
+var Difficulties = map[string]*Difficulty{
+	"easy":   {Name: "easy", ReactionTime: time.Millisecond * 800, AimError: 0.12},
+	"normal": {Name: "normal", ReactionTime: time.Millisecond * 400, AimError: 0.06},
+	"hard":   {Name: "hard", ReactionTime: time.Millisecond * 150, AimError: 0.02},
+}
+
+func GetDifficulty(name string) (*Difficulty, error) {
+	difficulty, ok := Difficulties[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown bot difficulty %q", name)
+	}
+	return difficulty, nil
+}
+
+// Bot drives a server side player. Each tick it scores its behaviors
+// (patrol, chase, strafe, retreat), runs the best one and feeds the result
+// to its player as the same Moves a client would send.
+type Bot struct {
+	Player     *Player
+	Difficulty *Difficulty
+	Behavior   string
+
+	target      *Player
+	attacker    *Player
+	seenFor     time.Duration
+	aimOffset   *math32.Vector3
+	aimTimer    time.Duration
+	waypoint    *math32.Vector3
+	route       []*math32.Vector3
+	destination *math32.Vector3
+	strafeLeft  bool
+	strafeTimer time.Duration
+	world       *World
+	unsubscribe func()
+}
+
+func NewBot(world *World, player *Player, difficulty *Difficulty) *Bot {
+	bot := &Bot{
+		Player:     player,
+		Difficulty: difficulty,
+		Behavior:   BotPatrol,
+		aimOffset:  math32.NewVec3(),
+		world:      world,
+	}
+	bot.unsubscribe = world.Subscribe(bot.onEvent)
+	return bot
+}
+
+// Remove stops the bot from listening to the world. It does not remove its
+// player.
+func (b *Bot) Remove() {
+	b.unsubscribe()
+}
+
+// onEvent remembers who last hit the bot, so it fights back instead of
+// going after whoever is nearest, and forgets them once either of them dies
+// or the attacker leaves.
+func (b *Bot) onEvent(event Event) {
+	switch e := event.(type) {
+	case *DamageEvent:
+		if e.Victim == b.Player.GetID() && e.Attacker != e.Victim {
+			b.attacker = b.world.GetPlayer(e.Attacker)
+		}
+	case *DeathEvent:
+		if b.attacker != nil && (e.Victim == b.attacker.GetID() || e.Victim == b.Player.GetID()) {
+			b.attacker = nil
+		}
+	case *RemovedEvent:
+		if b.attacker != nil && e.Model.GetID() == b.attacker.GetID() {
+			b.attacker = nil
+		}
+	case *PlayerRespawnedEvent:
+		if e.Player == b.Player {
+			b.attacker = nil
+		}
+	}
+}
+
+func (b *Bot) Update(deltaTime time.Duration) {
+	if b.Player.Dead {
+		b.target = nil
+		return
+	}
+
+	b.perceive(deltaTime)
+	b.Behavior = b.chooseBehavior()
+
+	moves := &Moves{Keys: make(map[string]bool)}
+	var facing *math32.Vector3
+	switch b.Behavior {
+	case BotRetreat:
+		facing = b.retreat(moves)
+	case BotChase, BotStrafe:
+		facing = b.attack(moves, deltaTime)
+	default:
+		facing = b.patrol(moves)
+	}
+	b.steer(moves, facing)
+	b.Player.RefreshMoves(moves)
+}
+
+// perceive keeps track of the nearest visible enemy and of how long it has
+// been in sight, which is what the reaction time is measured against.
+func (b *Bot) perceive(deltaTime time.Duration) {
+	var nearest *Player
+	nearestDistance := float32(conf.BotSightRange)
+	for _, player := range b.world.GetPlayers() {
+		if player == b.Player || player.Dead || player.IsCloaked() || IsTeammate(player, b.Player) {
+			continue
+		}
+		distance := player.Position.DistanceTo(b.Player.Position)
+		if distance > nearestDistance || !b.world.LineOfSight(b.Player.Position, player.Position) {
+			continue
+		}
+		nearest = player
+		nearestDistance = distance
+	}
+
+	if b.attacker != nil && !b.attacker.Dead && !b.attacker.IsCloaked() && !IsTeammate(b.attacker, b.Player) &&
+		b.attacker.Position.DistanceTo(b.Player.Position) <= conf.BotSightRange &&
+		b.world.LineOfSight(b.Player.Position, b.attacker.Position) {
+		nearest = b.attacker
+	}
+
+	if nearest != b.target {
+		b.target = nearest
+		b.seenFor = 0
+		return
+	}
+	b.seenFor += deltaTime
+}
+
+// chooseBehavior returns the behavior with the best utility.
+func (b *Bot) chooseBehavior() string {
+	if b.target == nil || b.seenFor < b.Difficulty.ReactionTime {
+		return BotPatrol
+	}
+	hp := b.Player.GetHP()
+	scores := map[string]float32{
+		BotChase:   0.5,
+		BotStrafe:  0,
+		BotRetreat: 0,
+	}
+	distance := b.target.Position.DistanceTo(b.Player.Position)
+	if distance < 25 {
+		scores[BotStrafe] = 0.6
+	}
+	if hp < conf.BotRetreatHP && hp < b.target.GetHP() {
+		scores[BotRetreat] = 1 - float32(hp)/conf.BotRetreatHP + 0.5
+	}
+
+	best := BotChase
+	for _, behavior := range []string{BotStrafe, BotRetreat} {
+		if scores[behavior] > scores[best] {
+			best = behavior
+		}
+	}
+	return best
+}
+
+// patrol flies from one spawn point or pickup to another.
+func (b *Bot) patrol(moves *Moves) *math32.Vector3 {
+	if b.waypoint == nil || b.waypoint.DistanceTo(b.Player.Position) < 5 {
+		b.waypoint = b.randomWaypoint()
+	}
+	moves.Keys[MoveForward] = true
+	return b.flyTo(b.waypoint)
+}
+
+// flyTo returns the direction to follow to reach the destination, going
+// around obstacles through the navigation graph of the map when there is one.
+func (b *Bot) flyTo(destination *math32.Vector3) *math32.Vector3 {
+	if b.world.Nav == nil || b.world.LineOfSight(b.Player.Position, destination) {
+		b.route = nil
+		return destination.Clone().Sub(b.Player.Position)
+	}
+	if b.destination == nil || !b.destination.Equals(destination) || len(b.route) == 0 {
+		b.destination = destination.Clone()
+		b.route = b.world.Nav.FindPath(b.Player.Position, destination)
+		if len(b.route) == 0 {
+			return destination.Clone().Sub(b.Player.Position)
+		}
+	}
+	for len(b.route) > 1 && b.route[0].DistanceTo(b.Player.Position) < conf.NavSpacing/2 {
+		b.route = b.route[1:]
+	}
+	return b.route[0].Clone().Sub(b.Player.Position)
+}
+
+func (b *Bot) randomWaypoint() *math32.Vector3 {
+	points := make([]*math32.Vector3, 0, len(b.world.SpawnPoints))
+	points = append(points, b.world.SpawnPoints...)
+	for _, pickup := range b.world.GetPickups() {
+		points = append(points, pickup.Position)
+	}
+	if len(points) == 0 {
+		return math32.NewVec3()
+	}
+	return points[rand.Intn(len(points))].Clone()
+}
+
+// attack aims at where the target will be when the shot reaches it, closes
+// in while far away and strafes once close.
+func (b *Bot) attack(moves *Moves, deltaTime time.Duration) *math32.Vector3 {
+	b.chooseWeapon()
+
+	aim := b.leadTarget().Sub(b.Player.Position)
+	b.aimTimer -= deltaTime
+	if b.aimTimer <= 0 {
+		// A new aim error every reaction time, so bad bots keep missing
+		// instead of drifting onto the target.
+		b.aimTimer = b.Difficulty.ReactionTime
+		b.aimOffset = math32.NewVector3(rand.Float32()*2-1, rand.Float32()*2-1, rand.Float32()*2-1).MultiplyScalar(b.Difficulty.AimError)
+	}
+	aim.Normalize().Add(b.aimOffset)
+
+	if b.Behavior == BotStrafe {
+		b.strafeTimer -= deltaTime
+		if b.strafeTimer <= 0 {
+			b.strafeTimer = time.Duration(500+rand.Intn(1000)) * time.Millisecond
+			b.strafeLeft = !b.strafeLeft
+		}
+		if b.strafeLeft {
+			moves.Keys[MoveLeft] = true
+		} else {
+			moves.Keys[MoveRight] = true
+		}
+	} else {
+		moves.Keys[MoveForward] = true
+	}
+
+	if b.Player.Direction.AngleTo(aim) < b.Difficulty.AimError+0.05 {
+		b.Player.Fire()
+	}
+	return aim
+}
+
+// leadTarget returns where the target will be once a projectile of the
+// current weapon reaches it.
+func (b *Bot) leadTarget() *math32.Vector3 {
+	position := b.target.Position.Clone()
+	weapon := b.Player.GetWeapon()
+	if weapon == nil || weapon.Hitscan || weapon.ProjectileSpeed <= 0 {
+		return position
+	}
+	// Projectiles inherit the velocity of the shooter.
+	velocity := b.target.Velocity.Clone().Sub(b.Player.Velocity)
+	ticks := position.DistanceTo(b.Player.Position) / weapon.ProjectileSpeed
+	return position.Add(velocity.MultiplyScalar(ticks))
+}
+
+// chooseWeapon picks a loaded weapon suited to the distance: spread weapons
+// up close, hitscan weapons far away.
+func (b *Bot) chooseWeapon() {
+	distance := b.target.Position.DistanceTo(b.Player.Position)
+	weapons := make([]*Weapon, 0, len(b.world.Weapons))
+	for _, weapon := range b.world.Weapons {
+		state := b.Player.getWeaponState(weapon)
+		if state.Ammo+state.Reserve > 0 && weapon.getReach() >= distance {
+			weapons = append(weapons, weapon)
+		}
+	}
+	if len(weapons) == 0 {
+		return
+	}
+	sort.SliceStable(weapons, func(i, j int) bool {
+		return b.weaponScore(weapons[i], distance) > b.weaponScore(weapons[j], distance)
+	})
+	b.Player.SwitchWeapon(weapons[0].Name)
+}
+
+func (b *Bot) weaponScore(weapon *Weapon, distance float32) float32 {
+	score := float32(weapon.Damage*weapon.ProjectileCount) * weapon.FireRate
+	if weapon.Spread > 0.05 && distance > 20 {
+		score /= 4
+	}
+	if weapon.Hitscan {
+		// Nothing to lead, so a strafing target is much easier to hit.
+		score *= 2
+	}
+	if weapon.ExplosionRadius > 0 && distance < weapon.ExplosionRadius*1.5 {
+		// Do not blow ourselves up.
+		score /= 10
+	}
+	return score
+}
+
+// retreat backs off while facing the threat, heading for a health pickup if
+// there is one.
+func (b *Bot) retreat(moves *Moves) *math32.Vector3 {
+	if pickup := b.nearestPickup(PickupHealth); pickup != nil {
+		moves.Keys[MoveForward] = true
+		return b.flyTo(pickup.Position)
+	}
+	moves.Keys[MoveBackward] = true
+	return b.target.Position.Clone().Sub(b.Player.Position)
+}
+
+func (b *Bot) nearestPickup(kind string) *Pickup {
+	var nearest *Pickup
+	nearestDistance := math32.Infinity
+	for _, pickup := range b.world.GetPickups() {
+		if !pickup.Active || pickup.Kind != kind {
+			continue
+		}
+		if distance := pickup.Position.DistanceTo(b.Player.Position); distance < nearestDistance {
+			nearest = pickup
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
+// steer turns toward the facing direction by at most conf.BotTurnSpeed per
+// tick on each axis, through the same turn keys a client uses.
+func (b *Bot) steer(moves *Moves, facing *math32.Vector3) {
+	if facing.Length() == 0 {
+		return
+	}
+	facing = facing.Clone().Normalize()
+	direction := b.Player.Direction.Clone().Normalize()
+	up := b.Player.Up.Clone().Normalize()
+	left := b.Player.GetLeftAxis().Normalize()
+
+	yaw := signedAngle(direction, facing, up)
+	pitch := signedAngle(direction, facing, left)
+
+	if yaw >= 0 {
+		moves.Keys[TurnLeft] = true
+		moves.VerticalAngleAngleSpeed = math32.Min(yaw, conf.BotTurnSpeed)
+	} else {
+		moves.Keys[TurnRight] = true
+		moves.VerticalAngleAngleSpeed = math32.Min(-yaw, conf.BotTurnSpeed)
+	}
+	if pitch >= 0 {
+		moves.Keys[TurnUp] = true
+		moves.HorizontalAngleSpeed = math32.Min(pitch, conf.BotTurnSpeed)
+	} else {
+		moves.Keys[TurnDown] = true
+		moves.HorizontalAngleSpeed = math32.Min(-pitch, conf.BotTurnSpeed)
+	}
+}
+
+// signedAngle returns the angle to rotate from around axis to get as close
+// as possible to to.
+func signedAngle(from, to, axis *math32.Vector3) float32 {
+	projected := to.Clone().Sub(axis.Clone().MultiplyScalar(to.Dot(axis)))
+	if projected.Length() == 0 {
+		return 0
+	}
+	angle := from.AngleTo(projected)
+	if from.Clone().Cross(projected).Dot(axis) < 0 {
+		return -angle
+	}
+	return angle
+}
@@ -9,41 +9,79 @@ import (
 
 type Bullet struct {
 	ID       string
-	Player   *Player
+	Player   *Player `json:"-"`
+	Owner    string
+	Weapon   string
+	Damage   int
 	Position *math32.Vector3
 	Velocity *math32.Vector3
 
-	hp      int
-	deleted bool
-	world   *World
+	lifetime time.Duration
+	deleted  bool
+	world    *World
 }
 
-func NewBullet(world *World, player *Player, velocity *math32.Vector3) *Bullet {
+func NewBullet(world *World, player *Player, weapon *Weapon, velocity *math32.Vector3) *Bullet {
 	return &Bullet{
 		ID:       xid.New().String(),
 		Player:   player,
+		Owner:    player.GetID(),
+		Weapon:   weapon.Name,
+		Damage:   weapon.Damage,
 		Position: player.Position.Clone().Add(player.Velocity),
 		Velocity: velocity,
+		lifetime: weapon.GetLifetime(),
 		world:    world,
-		hp:       10,
 	}
 }
 
 func (b *Bullet) Update(deltaTime time.Duration) {
 	b.Position.Add(b.Velocity)
+	if b.lifetime > 0 {
+		b.lifetime -= deltaTime
+		if b.lifetime <= 0 {
+			b.detonate(b.Position, false)
+			return
+		}
+	}
 	for _, player := range b.world.players {
-		if player != b.Player {
+		if player != b.Player && !player.Dead {
 			if player.GetHitBox().ContainsPoint(b.Position) {
-				player.BulletHit(b)
-				b.deleted = true
+				b.detonate(b.Position, player.BulletHit(b))
+				return
 			}
 		}
 	}
-	if b.Position.Length() > 100 {
+	for _, model := range b.world.models {
+		if missile, ok := model.(*Missile); ok && missile.Player != b.Player && missile.GetHitBox().ContainsPoint(b.Position) {
+			missile.Hit(b.Damage)
+			b.detonate(b.Position, false)
+			return
+		}
+		if enemy, ok := model.(*Enemy); ok && !enemy.deleted && enemy.GetHitBox().ContainsPoint(b.Position) {
+			b.detonate(b.Position, enemy.takeDamage(b.Damage, b.Player, b.Weapon))
+			return
+		}
+	}
+	if b.world.Level.ContainsPoint(b.Position) {
+		// Detonate on the surface rather than inside the obstacle.
+		b.detonate(b.Position.Clone().Sub(b.Velocity), false)
+		return
+	}
+	if b.world.Level.OutOfBounds(b.Position) {
 		b.deleted = true
 	}
 }
 
+// detonate removes the bullet, exploding it if its weapon is explosive. hit
+// tells whether the bullet itself hurt someone.
+func (b *Bullet) detonate(position *math32.Vector3, hit bool) {
+	b.deleted = true
+	if b.world.detonate(b.Player, b.Weapon, position) || hit {
+		b.world.recordHit(b.Player)
+	}
+}
+
 func (b *Bullet) UpdatePosition(deltaTime time.Duration) {
 	b.Position.Add(b.Velocity)
 }
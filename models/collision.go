@@ -0,0 +1,58 @@
+package models
+
+import (
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+type Collision struct {
+	PlayerA string
+	PlayerB string
+	Point   *math32.Vector3
+	Impulse float32
+	Damage  int
+}
+
+// collidePlayers resolves a sphere-sphere overlap between two ships. It
+// returns nil when the hitboxes do not touch.
+func collidePlayers(a, b *Player) *Collision {
+	hitBoxA := a.GetHitBox()
+	hitBoxB := b.GetHitBox()
+
+	normal := a.Position.Clone().Sub(b.Position)
+	distance := normal.Length()
+	overlap := hitBoxA.Radius + hitBoxB.Radius - distance
+	if overlap <= 0 {
+		return nil
+	}
+	if distance == 0 {
+		normal = a.GetLeftAxis()
+	}
+	normal.Normalize()
+
+	a.Position.Add(normal.Clone().MultiplyScalar(overlap / 2))
+	b.Position.Sub(normal.Clone().MultiplyScalar(overlap / 2))
+
+	collision := &Collision{
+		PlayerA: a.GetID(),
+		PlayerB: b.GetID(),
+		Point:   b.Position.Clone().Add(normal.Clone().MultiplyScalar(hitBoxB.Radius)),
+	}
+
+	approachSpeed := -a.Velocity.Clone().Sub(b.Velocity).Dot(normal)
+	if approachSpeed <= 0 {
+		return collision
+	}
+
+	// Both ships have the same mass, so the impulse is shared evenly.
+	impulse := (1 + conf.ShipRestitution) * approachSpeed / 2
+	a.Velocity.Add(normal.Clone().MultiplyScalar(impulse))
+	b.Velocity.Sub(normal.Clone().MultiplyScalar(impulse))
+	collision.Impulse = impulse
+
+	if conf.RamDamage && approachSpeed > conf.RamDamageMinSpeed {
+		collision.Damage = int((approachSpeed - conf.RamDamageMinSpeed) * conf.RamDamageFactor)
+	}
+
+	return collision
+}
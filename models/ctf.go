@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+const ModeCaptureTheFlag = "ctf"
+
+// CaptureTheFlag scores one point per capture. Players take the enemy flag
+// by touching it and capture it by bringing it to their own base while
+// their flag is home.
+type CaptureTheFlag struct {
+	scores map[string]int
+	world  *World
+}
+
+func NewCaptureTheFlag(world *World) *CaptureTheFlag {
+	return &CaptureTheFlag{
+		scores: make(map[string]int),
+		world:  world,
+	}
+}
+
+func (m *CaptureTheFlag) GetName() string {
+	return ModeCaptureTheFlag
+}
+
+func (m *CaptureTheFlag) IsTeamMode() bool {
+	return true
+}
+
+func (m *CaptureTheFlag) GetScores() []*Score {
+	return teamScores(m.scores)
+}
+
+// Reset clears the scores and brings every flag back to its base.
+func (m *CaptureTheFlag) Reset() {
+	m.scores = make(map[string]int)
+	for _, flag := range m.world.GetFlags() {
+		if flag.State != FlagHome {
+			flag.Apply(flag.newEvent(FlagActionReturn, nil))
+		}
+	}
+}
+
+func (m *CaptureTheFlag) Update(deltaTime time.Duration) {
+	for _, flag := range m.world.GetFlags() {
+		if carrier := flag.update(deltaTime); carrier != nil {
+			m.scores[carrier.Team]++
+		}
+	}
+}
+
+func (m *CaptureTheFlag) OnJoin(player *Player) {
+	player.Team = m.world.smallestTeam(m.scores)
+}
+
+func (m *CaptureTheFlag) OnKill(victim, killer *Player) {
+}
+
+func (m *CaptureTheFlag) CanRespawn(player *Player) bool {
+	return true
+}
+
+func (m *CaptureTheFlag) IsOver() bool {
+	return false
+}
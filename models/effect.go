@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lambher/video-game/conf"
+)
+
+// collect applies a pickup to the player. It returns false when the pickup
+// would be useless, so it stays in the arena.
+func (p *Player) collect(kind string) bool {
+	switch kind {
+	case PickupHealth:
+		if p.hp >= maxHP {
+			return false
+		}
+		hp := p.hp + conf.HealthPickup
+		if hp > maxHP {
+			hp = maxHP
+		}
+		p.SetHP(hp)
+	case PickupAmmo:
+		p.refillAmmo()
+	case PickupShield:
+		p.Shield = conf.ShieldPickup
+		p.addEffect(PickupShield, conf.ShieldDuration)
+	case PickupSpeed:
+		p.addEffect(PickupSpeed, conf.SpeedBoostDuration)
+	case PickupDamage:
+		p.addEffect(PickupDamage, conf.DamageAmplifierDuration)
+	case PickupCloak:
+		p.addEffect(PickupCloak, conf.CloakDuration)
+	default:
+		return false
+	}
+	return true
+}
+
+func (p *Player) refillAmmo() {
+	for _, weapon := range p.world.Weapons {
+		if p.arsenal == nil {
+			p.arsenal = make(map[string]*WeaponState)
+		}
+		state, ok := p.arsenal[weapon.Name]
+		if !ok {
+			state = newWeaponState(weapon)
+			p.arsenal[weapon.Name] = state
+		}
+		state.Reserve = weapon.ReserveAmmo
+		p.onWeaponState(state)
+	}
+}
+
+func (p *Player) addEffect(kind string, duration time.Duration) {
+	if p.Effects == nil {
+		p.Effects = make(map[string]time.Duration)
+	}
+	p.Effects[kind] = duration
+}
+
+func (p Player) HasEffect(kind string) bool {
+	return p.Effects[kind] > 0
+}
+
+// IsCloaked tells whether the player is hidden from missile locks, bots and
+// the target display.
+func (p Player) IsCloaked() bool {
+	return p.HasEffect(PickupCloak)
+}
+
+func (p *Player) updateEffects(deltaTime time.Duration) {
+	for kind, remaining := range p.Effects {
+		remaining -= deltaTime
+		if remaining > 0 {
+			p.Effects[kind] = remaining
+			continue
+		}
+		delete(p.Effects, kind)
+		if kind == PickupShield {
+			p.Shield = 0
+		}
+	}
+}
+
+func (p Player) speedMultiplier() float32 {
+	if p.HasEffect(PickupSpeed) {
+		return conf.SpeedBoost
+	}
+	return 1
+}
+
+func (p Player) damageMultiplier() float32 {
+	if p.HasEffect(PickupDamage) {
+		return conf.DamageAmplifier
+	}
+	return 1
+}
+
+// absorb takes as much damage as possible from the shield and returns the
+// rest.
+func (p *Player) absorb(amount int) int {
+	if p.Shield <= 0 {
+		return amount
+	}
+	if amount <= p.Shield {
+		p.Shield -= amount
+		return 0
+	}
+	amount -= p.Shield
+	p.Shield = 0
+	delete(p.Effects, PickupShield)
+	return amount
+}
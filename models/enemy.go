@@ -0,0 +1,214 @@
+package models
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+	"github.com/rs/xid"
+)
+
+const WeaponEnemy = "enemy"
+
+const enemyRadius = 1
+
+// Enemy is a server controlled drone of the survival mode. It chases the
+// nearest living player and rams them, dealing Damage at most once per
+// conf.EnemyAttackCooldown.
+type Enemy struct {
+	ID        string
+	Position  *math32.Vector3
+	Velocity  *math32.Vector3
+	Direction *math32.Vector3
+	Target    string
+	HP        int
+	MaxHP     int
+	Damage    int
+	Speed     float32
+
+	cooldown time.Duration
+	side     float32
+	deleted  bool
+	world    *World
+}
+
+// NewEnemy spawns an enemy near a random spawn point. Toughness scales its
+// health, damage and speed.
+func NewEnemy(world *World, toughness float32) *Enemy {
+	position := math32.Vector3{}
+	if len(world.SpawnPoints) > 0 {
+		position = *world.SpawnPoints[rand.Intn(len(world.SpawnPoints))]
+	}
+	position.Add(math32.NewVector3(rand.Float32()*10-5, rand.Float32()*10-5, rand.Float32()*10-5))
+	hitBox := math32.NewSphere(&position, enemyRadius)
+	world.Level.PushOut(hitBox)
+
+	side := float32(1)
+	if rand.Intn(2) == 0 {
+		side = -1
+	}
+	hp := int(conf.EnemyHP * toughness)
+	return &Enemy{
+		ID:        xid.New().String(),
+		Position:  hitBox.Center.Clone(),
+		Velocity:  math32.NewVec3(),
+		Direction: math32.NewVector3(0, 0, -1),
+		HP:        hp,
+		MaxHP:     hp,
+		Damage:    int(conf.EnemyDamage * toughness),
+		Speed:     conf.EnemySpeed * math32.Sqrt(toughness),
+		side:      side,
+		world:     world,
+	}
+}
+
+func (e *Enemy) Update(deltaTime time.Duration) {
+	if e.cooldown > 0 {
+		e.cooldown -= deltaTime
+	}
+
+	target := e.findTarget()
+	if target == nil {
+		e.Target = ""
+		e.Velocity.MultiplyScalar(0.9)
+	} else {
+		e.Target = target.GetID()
+		desired := target.Position.Clone().Sub(e.Position).Normalize().MultiplyScalar(e.Speed)
+		e.Velocity.Lerp(desired, 0.1)
+		e.Direction = desired.Normalize()
+	}
+	e.separate()
+	e.Position.Add(e.Velocity)
+
+	hitBox := e.GetHitBox()
+	for _, normal := range e.world.Level.PushOut(hitBox) {
+		if speed := e.Velocity.Dot(normal); speed < 0 {
+			e.Velocity.Sub(normal.Clone().MultiplyScalar(speed))
+		}
+		// Follow the wall to get around obstacles between the enemy and
+		// its target.
+		slide := normal.Clone().Cross(math32.NewVector3(0, 1, 0))
+		if slide.Length() == 0 {
+			slide.Set(1, 0, 0)
+		}
+		e.Velocity.Add(slide.Normalize().MultiplyScalar(e.Speed * e.side))
+	}
+	e.Position.Copy(&hitBox.Center)
+
+	if e.world.Match != nil && !e.world.Match.AllowsCombat() {
+		return
+	}
+	if target != nil && e.cooldown <= 0 && e.Position.DistanceTo(target.Position) <= enemyRadius+target.GetHitBox().Radius {
+		e.cooldown = conf.EnemyAttackCooldown
+		target.takeDamage(e.Damage, nil, WeaponEnemy)
+	}
+}
+
+// findTarget returns the nearest living player.
+func (e *Enemy) findTarget() *Player {
+	var target *Player
+	nearest := math32.Infinity
+	for _, player := range e.world.players {
+		if player.Dead {
+			continue
+		}
+		if distance := player.Position.DistanceTo(e.Position); distance < nearest {
+			target = player
+			nearest = distance
+		}
+	}
+	return target
+}
+
+// separate pushes the enemy away from the other enemies it overlaps so a
+// wave does not collapse into a single point.
+func (e *Enemy) separate() {
+	for _, model := range e.world.models {
+		other, ok := model.(*Enemy)
+		if !ok || other == e {
+			continue
+		}
+		away := e.Position.Clone().Sub(other.Position)
+		if distance := away.Length(); distance > 0 && distance < enemyRadius*2 {
+			e.Velocity.Add(away.Normalize().MultiplyScalar(e.Speed * 0.5))
+		}
+	}
+}
+
+// takeDamage hurts the enemy and tells whether it took the hit.
+func (e *Enemy) takeDamage(amount int, attacker *Player, weapon string) bool {
+	if e.deleted {
+		return false
+	}
+	if attacker != nil {
+		amount = int(float32(amount) * attacker.damageMultiplier())
+	}
+	amount = e.world.modifyDamage(e, attacker, weapon, amount)
+	if attacker != nil {
+		e.world.GetStats(attacker).DamageDealt += amount
+	}
+	e.HP -= amount
+	e.world.onDamage(e, attacker, amount)
+	if e.HP > 0 {
+		return true
+	}
+	e.HP = 0
+	e.deleted = true
+	if attacker != nil {
+		e.world.GetStats(attacker).Kills++
+	}
+	return true
+}
+
+func (e *Enemy) Refresh(enemy Enemy) {
+	e.Position = enemy.Position
+	e.Velocity = enemy.Velocity
+	e.Direction = enemy.Direction
+	e.Target = enemy.Target
+	e.HP = enemy.HP
+}
+
+func (e *Enemy) UpdatePosition(deltaTime time.Duration) {
+	e.Position.Add(e.Velocity)
+}
+
+func (e Enemy) GetHitBox() *math32.Sphere {
+	return math32.NewSphere(e.Position, enemyRadius)
+}
+
+func (e Enemy) IsDeleted() bool {
+	return e.deleted
+}
+
+func (e Enemy) GetID() string {
+	return e.ID
+}
+
+func (w *World) AddEnemy(enemy *Enemy) {
+	if w.models == nil {
+		w.models = make(map[string]Model)
+	}
+	if enemy.world == nil {
+		enemy.world = w
+	}
+	w.models[enemy.ID] = enemy
+	w.events.Publish(&EnemySpawnedEvent{Enemy: enemy})
+}
+
+func (w *World) GetEnemies() []*Enemy {
+	enemies := make([]*Enemy, 0)
+	for _, model := range w.models {
+		if enemy, ok := model.(*Enemy); ok {
+			enemies = append(enemies, enemy)
+		}
+	}
+	return enemies
+}
+
+func (w *World) GetEnemy(id string) *Enemy {
+	if enemy, ok := w.models[id].(*Enemy); ok {
+		return enemy
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package models
+
+// Event is something that happened in the world. Subscribers switch on its
+// concrete type: *PlayerJoinedEvent, *DamageEvent, *HealthEvent, *Collision,
+// *DeathEvent, *PlayerRespawnedEvent, *WeaponStateEvent,
+// *ProjectileSpawnedEvent, *HitscanEvent, *ExplosionEvent,
+// *PickupSpawnedEvent, *PickupEvent, *MatchState, *FlagAddedEvent,
+// *FlagEvent, *ZoneAddedEvent, *EnemySpawnedEvent, *CheckpointEvent,
+// *GhostEvent or *RemovedEvent.
+type Event interface {
+	event()
+}
+
+type PlayerJoinedEvent struct {
+	Player *Player
+}
+
+type PlayerRespawnedEvent struct {
+	Player *Player
+}
+
+type WeaponStateEvent struct {
+	Player *Player
+	State  *WeaponState
+}
+
+// ProjectileSpawnedEvent is sent for every *Bullet and *Missile fired.
+type ProjectileSpawnedEvent struct {
+	Projectile Model
+}
+
+type PickupSpawnedEvent struct {
+	Pickup *Pickup
+}
+
+type FlagAddedEvent struct {
+	Flag *Flag
+}
+
+type ZoneAddedEvent struct {
+	Zone *Zone
+}
+
+type EnemySpawnedEvent struct {
+	Enemy *Enemy
+}
+
+type GhostEvent struct {
+	Ghost *Ghost
+}
+
+// RemovedEvent is sent when a player leaves or a model is deleted.
+type RemovedEvent struct {
+	Model Model
+}
+
+func (*PlayerJoinedEvent) event()      {}
+func (*DamageEvent) event()            {}
+func (*HealthEvent) event()            {}
+func (*Collision) event()              {}
+func (*DeathEvent) event()             {}
+func (*PlayerRespawnedEvent) event()   {}
+func (*WeaponStateEvent) event()       {}
+func (*ProjectileSpawnedEvent) event() {}
+func (*HitscanEvent) event()           {}
+func (*ExplosionEvent) event()         {}
+func (*PickupSpawnedEvent) event()     {}
+func (*PickupEvent) event()            {}
+func (*MatchState) event()             {}
+func (*FlagAddedEvent) event()         {}
+func (*FlagEvent) event()              {}
+func (*ZoneAddedEvent) event()         {}
+func (*EnemySpawnedEvent) event()      {}
+func (*CheckpointEvent) event()        {}
+func (*GhostEvent) event()             {}
+func (*RemovedEvent) event()           {}
+
+type EventHandler func(event Event)
+
+type subscription struct {
+	handler EventHandler
+}
+
+// EventBus delivers every published event to its subscribers, in the order
+// they subscribed. Handlers may subscribe or unsubscribe while handling an
+// event; the change applies from the next event on. Like the World it
+// belongs to, it is not safe for concurrent use.
+type EventBus struct {
+	subscriptions []*subscription
+}
+
+// Subscribe adds a handler and returns the function removing it.
+func (b *EventBus) Subscribe(handler EventHandler) func() {
+	s := &subscription{handler: handler}
+	b.subscriptions = append(b.subscriptions, s)
+
+	return func() {
+		b.unsubscribe(s)
+	}
+}
+
+func (b *EventBus) unsubscribe(s *subscription) {
+	for i, other := range b.subscriptions {
+		if other == s {
+			// Copy instead of shifting in place, Publish may be iterating
+			// over the old slice.
+			subscriptions := make([]*subscription, 0, len(b.subscriptions)-1)
+			subscriptions = append(subscriptions, b.subscriptions[:i]...)
+			b.subscriptions = append(subscriptions, b.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *EventBus) Publish(event Event) {
+	// Iterate over the subscriptions of the moment: Subscribe and
+	// unsubscribe never change the elements of this slice.
+	for _, s := range b.subscriptions {
+		s.handler(event)
+	}
+}
+
+// ListenerHandler adapts an EventListener to the event bus, calling the
+// method matching each event.
+func ListenerHandler(l EventListener) EventHandler {
+	return func(event Event) {
+		switch e := event.(type) {
+		case *PlayerJoinedEvent:
+			l.OnAddPlayer(e.Player)
+		case *DamageEvent:
+			l.OnPlayerDamage(e)
+		case *HealthEvent:
+			l.OnPlayerHealth(e)
+		case *Collision:
+			l.OnPlayersCollide(e)
+		case *DeathEvent:
+			l.OnPlayerDeath(e)
+		case *PlayerRespawnedEvent:
+			l.OnPlayerRespawn(e.Player)
+		case *WeaponStateEvent:
+			l.OnWeaponState(e.Player, e.State)
+		case *ProjectileSpawnedEvent:
+			switch projectile := e.Projectile.(type) {
+			case *Bullet:
+				l.OnAddBullet(projectile)
+			case *Missile:
+				l.OnAddMissile(projectile)
+			}
+		case *HitscanEvent:
+			l.OnHitscan(e)
+		case *ExplosionEvent:
+			l.OnExplosion(e)
+		case *PickupSpawnedEvent:
+			l.OnAddPickup(e.Pickup)
+		case *PickupEvent:
+			l.OnPickup(e)
+		case *MatchState:
+			l.OnMatchState(e)
+		case *FlagAddedEvent:
+			l.OnAddFlag(e.Flag)
+		case *FlagEvent:
+			l.OnFlag(e)
+		case *ZoneAddedEvent:
+			l.OnAddZone(e.Zone)
+		case *EnemySpawnedEvent:
+			l.OnAddEnemy(e.Enemy)
+		case *CheckpointEvent:
+			l.OnCheckpoint(e)
+		case *GhostEvent:
+			l.OnGhost(e.Ghost)
+		case *RemovedEvent:
+			l.OnRemoveModel(e.Model)
+		}
+	}
+}
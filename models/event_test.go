@@ -0,0 +1,114 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recorder subscribes named handlers to a bus and records which of them
+// received each event.
+type recorder struct {
+	bus          *EventBus
+	calls        []string
+	unsubscribes map[string]func()
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		bus:          &EventBus{},
+		unsubscribes: make(map[string]func()),
+	}
+}
+
+func (r *recorder) subscribe(name string, then func()) {
+	r.unsubscribes[name] = r.bus.Subscribe(func(event Event) {
+		r.calls = append(r.calls, name)
+		if then != nil {
+			then()
+		}
+	})
+}
+
+func (r *recorder) publish() []string {
+	r.calls = nil
+	r.bus.Publish(&RemovedEvent{})
+	return r.calls
+}
+
+func TestEventBusOrder(t *testing.T) {
+	r := newRecorder()
+	r.subscribe("a", nil)
+	r.subscribe("b", nil)
+	r.subscribe("c", nil)
+
+	if calls := r.publish(); !reflect.DeepEqual(calls, []string{"a", "b", "c"}) {
+		t.Errorf("calls = %v, want [a b c]", calls)
+	}
+}
+
+func TestEventBusUnsubscribe(t *testing.T) {
+	r := newRecorder()
+	r.subscribe("a", nil)
+	r.subscribe("b", nil)
+	r.subscribe("c", nil)
+
+	r.unsubscribes["b"]()
+	if calls := r.publish(); !reflect.DeepEqual(calls, []string{"a", "c"}) {
+		t.Errorf("calls = %v, want [a c]", calls)
+	}
+
+	// Unsubscribing twice is harmless.
+	r.unsubscribes["b"]()
+	if calls := r.publish(); !reflect.DeepEqual(calls, []string{"a", "c"}) {
+		t.Errorf("calls = %v, want [a c]", calls)
+	}
+}
+
+func TestEventBusChangesDuringPublish(t *testing.T) {
+	tests := []struct {
+		name   string
+		during func(r *recorder)
+		first  []string
+		second []string
+	}{
+		{
+			name:   "unsubscribe itself",
+			during: func(r *recorder) { r.unsubscribes["a"]() },
+			first:  []string{"a", "b", "c"},
+			second: []string{"b", "c"},
+		},
+		{
+			name:   "unsubscribe the next handler",
+			during: func(r *recorder) { r.unsubscribes["b"]() },
+			first:  []string{"a", "b", "c"},
+			second: []string{"a", "c"},
+		},
+		{
+			name:   "subscribe a new handler",
+			during: func(r *recorder) { r.subscribe("d", nil) },
+			first:  []string{"a", "b", "c"},
+			second: []string{"a", "b", "c", "d"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newRecorder()
+			done := false
+			r.subscribe("a", func() {
+				if !done {
+					done = true
+					test.during(r)
+				}
+			})
+			r.subscribe("b", nil)
+			r.subscribe("c", nil)
+
+			if calls := r.publish(); !reflect.DeepEqual(calls, test.first) {
+				t.Errorf("first event calls = %v, want %v", calls, test.first)
+			}
+			if calls := r.publish(); !reflect.DeepEqual(calls, test.second) {
+				t.Errorf("second event calls = %v, want %v", calls, test.second)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"github.com/g3n/engine/math32"
+)
+
+type ExplosionEvent struct {
+	Owner    string
+	Weapon   string
+	Position *math32.Vector3
+	Radius   float32
+}
+
+// Explode applies the weapon radial damage and knockback to every living
+// player in range. Both fall off linearly with the distance and players
+// hidden behind an obstacle are spared.
+func (w *World) Explode(owner *Player, weapon *Weapon, position *math32.Vector3) bool {
+	event := &ExplosionEvent{
+		Weapon:   weapon.Name,
+		Position: position.Clone(),
+		Radius:   weapon.ExplosionRadius,
+	}
+	if owner != nil {
+		event.Owner = owner.GetID()
+	}
+	w.events.Publish(event)
+
+	hit := false
+	for _, player := range w.GetPlayers() {
+		if player.Dead {
+			continue
+		}
+		direction := player.Position.Clone().Sub(position)
+		distance := direction.Length()
+		if distance > weapon.ExplosionRadius {
+			continue
+		}
+		if !w.LineOfSight(position, player.Position) {
+			continue
+		}
+
+		falloff := 1 - distance/weapon.ExplosionRadius
+		if distance > 0 {
+			knockback := w.modifyKnockback(player, weapon.Knockback*falloff)
+			player.push(direction.Normalize().MultiplyScalar(knockback))
+		}
+		if damage := int(float32(weapon.ExplosionDamage) * falloff); damage > 0 {
+			if player.takeDamage(damage, owner, weapon.Name) && player != owner {
+				hit = true
+			}
+		}
+	}
+
+	for _, enemy := range w.GetEnemies() {
+		direction := enemy.Position.Clone().Sub(position)
+		distance := direction.Length()
+		if distance > weapon.ExplosionRadius || !w.LineOfSight(position, enemy.Position) {
+			continue
+		}
+
+		falloff := 1 - distance/weapon.ExplosionRadius
+		if distance > 0 {
+			knockback := w.modifyKnockback(enemy, weapon.Knockback*falloff)
+			enemy.Velocity.Add(direction.Normalize().MultiplyScalar(knockback))
+		}
+		if damage := int(float32(weapon.ExplosionDamage) * falloff); damage > 0 {
+			if enemy.takeDamage(damage, owner, weapon.Name) {
+				hit = true
+			}
+		}
+	}
+	return hit
+}
+
+// detonate explodes the weapon at position if it is explosive. It tells
+// whether the explosion hurt anyone but its owner.
+func (w *World) detonate(owner *Player, weaponName string, position *math32.Vector3) bool {
+	weapon := w.GetWeapon(weaponName)
+	if weapon == nil || weapon.ExplosionRadius <= 0 {
+		return false
+	}
+	return w.Explode(owner, weapon, position)
+}
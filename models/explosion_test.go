@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestExplosionKnockback(t *testing.T) {
+	weapon := &Weapon{Name: "rocket", ExplosionRadius: 10, Knockback: 1}
+	for _, moving := range []bool{false, true} {
+		world := &World{}
+		player := NewPlayer("player", world, "player", math32.Vector3{})
+		world.AddPlayer(player)
+		player.MoveForward(moving)
+
+		world.Explode(nil, weapon, math32.NewVector3(5, 0, 0))
+		for i := 0; i < 10; i++ {
+			player.Update(16 * time.Millisecond)
+		}
+
+		if player.Position.X > -1 {
+			t.Errorf("moving %v: player pushed to x = %v, want < -1", moving, player.Position.X)
+		}
+		if moving && player.Position.Z > -0.5 {
+			t.Errorf("moving %v: player lost its own move, z = %v", moving, player.Position.Z)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+	"github.com/rs/xid"
+)
+
+const (
+	FlagHome    = "home"
+	FlagCarried = "carried"
+	FlagDropped = "dropped"
+)
+
+const (
+	FlagActionPickup  = "pickup"
+	FlagActionDrop    = "drop"
+	FlagActionReturn  = "return"
+	FlagActionCapture = "capture"
+)
+
+const flagRadius = 2
+
+// Flag is a team flag for capture the flag. It is driven by the
+// CaptureTheFlag mode; on its own it only follows its carrier.
+type Flag struct {
+	ID       string
+	Team     string
+	Base     *math32.Vector3
+	Position *math32.Vector3
+	State    string
+	Carrier  string
+
+	returnTimer time.Duration
+	world       *World
+}
+
+// FlagBase is where a team flag stands in the map file.
+type FlagBase struct {
+	Team     string
+	Position *math32.Vector3
+}
+
+type FlagEvent struct {
+	Flag       string
+	Team       string
+	Action     string
+	Player     string
+	PlayerName string
+	Position   *math32.Vector3
+}
+
+func NewFlag(world *World, team string, base math32.Vector3) *Flag {
+	return &Flag{
+		ID:       xid.New().String(),
+		Team:     team,
+		Base:     &base,
+		Position: base.Clone(),
+		State:    FlagHome,
+		world:    world,
+	}
+}
+
+// Apply changes the flag state according to the action and notifies the
+// listener. The server and its clients go through it alike.
+func (f *Flag) Apply(event *FlagEvent) {
+	switch event.Action {
+	case FlagActionPickup:
+		f.State = FlagCarried
+		f.Carrier = event.Player
+	case FlagActionDrop:
+		f.State = FlagDropped
+		f.Carrier = ""
+		if event.Position != nil {
+			f.Position = event.Position.Clone()
+		}
+	case FlagActionReturn, FlagActionCapture:
+		f.State = FlagHome
+		f.Carrier = ""
+		f.Position = f.Base.Clone()
+	}
+	f.world.events.Publish(event)
+}
+
+func (f *Flag) newEvent(action string, player *Player) *FlagEvent {
+	event := &FlagEvent{
+		Flag:     f.ID,
+		Team:     f.Team,
+		Action:   action,
+		Position: f.Position.Clone(),
+	}
+	if player != nil {
+		event.Player = player.GetID()
+		event.PlayerName = player.Name
+	}
+	return event
+}
+
+// update runs the flag rules for one tick and returns the player who
+// captured it, if any.
+func (f *Flag) update(deltaTime time.Duration) *Player {
+	switch f.State {
+	case FlagCarried:
+		carrier := f.world.GetPlayer(f.Carrier)
+		if carrier == nil || carrier.Dead {
+			f.Apply(f.newEvent(FlagActionDrop, carrier))
+			f.returnTimer = conf.FlagReturnTime
+			return nil
+		}
+		f.Position = carrier.Position.Clone()
+		if home := f.world.getTeamFlag(carrier.Team); home != nil && home.State == FlagHome && f.touches(carrier, home.Base) {
+			f.Apply(f.newEvent(FlagActionCapture, carrier))
+			return carrier
+		}
+	case FlagDropped:
+		f.returnTimer -= deltaTime
+		if f.returnTimer <= 0 {
+			f.Apply(f.newEvent(FlagActionReturn, nil))
+			return nil
+		}
+		fallthrough
+	case FlagHome:
+		for _, player := range f.world.GetPlayers() {
+			if player.Dead || player.Team == "" || !f.touches(player, f.Position) {
+				continue
+			}
+			if player.Team != f.Team {
+				f.Apply(f.newEvent(FlagActionPickup, player))
+				return nil
+			}
+			if f.State == FlagDropped {
+				f.Apply(f.newEvent(FlagActionReturn, player))
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Flag) touches(player *Player, position *math32.Vector3) bool {
+	return player.Position.DistanceTo(position) <= player.GetHitBox().Radius+flagRadius
+}
+
+func (f *Flag) Update(deltaTime time.Duration) {
+}
+
+// UpdatePosition keeps a carried flag on its carrier.
+func (f *Flag) UpdatePosition(deltaTime time.Duration) {
+	if f.State != FlagCarried {
+		return
+	}
+	if carrier := f.world.GetPlayer(f.Carrier); carrier != nil {
+		f.Position = carrier.Position.Clone()
+	}
+}
+
+func (f Flag) IsDeleted() bool {
+	return false
+}
+
+func (f Flag) GetID() string {
+	return f.ID
+}
+
+func (w *World) AddFlag(flag *Flag) {
+	if w.models == nil {
+		w.models = make(map[string]Model)
+	}
+	if flag.world == nil {
+		flag.world = w
+	}
+	w.models[flag.ID] = flag
+	w.events.Publish(&FlagAddedEvent{Flag: flag})
+}
+
+func (w *World) GetFlags() []*Flag {
+	flags := make([]*Flag, 0)
+	for _, model := range w.models {
+		if flag, ok := model.(*Flag); ok {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+func (w *World) GetFlag(id string) *Flag {
+	if flag, ok := w.models[id].(*Flag); ok {
+		return flag
+	}
+	return nil
+}
+
+func (w *World) getTeamFlag(team string) *Flag {
+	for _, flag := range w.GetFlags() {
+		if flag.Team == team {
+			return flag
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"github.com/g3n/engine/math32"
+)
+
+// Gate is a race checkpoint: a ring of Radius around Position that has to be
+// flown through along Normal.
+type Gate struct {
+	Position *math32.Vector3
+	Normal   *math32.Vector3
+	Radius   float32
+}
+
+// Crossed tells whether a move from one point to the other went through the
+// ring in the right direction.
+func (g *Gate) Crossed(from, to *math32.Vector3) bool {
+	normal := g.Normal.Clone().Normalize()
+	before := from.Clone().Sub(g.Position).Dot(normal)
+	after := to.Clone().Sub(g.Position).Dot(normal)
+	if before >= 0 || after < 0 {
+		return false
+	}
+	t := before / (before - after)
+	point := from.Clone().Lerp(to, t)
+	return point.DistanceTo(g.Position) <= g.Radius
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lambher/video-game/conf"
+)
+
+const (
+	ModeKingOfTheHill     = "koth"
+	ModeTeamKingOfTheHill = "tkoth"
+)
+
+// KingOfTheHill awards a point every conf.ZoneScoreInterval to whoever owns
+// a zone and holds it alone. With several zones in the map only one is
+// active at a time and the active zone moves every conf.ZoneRotationTime.
+type KingOfTheHill struct {
+	teams       bool
+	scores      map[string]int
+	scoreTimer  time.Duration
+	rotateTimer time.Duration
+	activeZone  int
+	world       *World
+}
+
+func NewKingOfTheHill(world *World, teams bool) *KingOfTheHill {
+	mode := &KingOfTheHill{
+		teams: teams,
+		world: world,
+	}
+	mode.Reset()
+	return mode
+}
+
+func (m *KingOfTheHill) GetName() string {
+	if m.teams {
+		return ModeTeamKingOfTheHill
+	}
+	return ModeKingOfTheHill
+}
+
+func (m *KingOfTheHill) IsTeamMode() bool {
+	return m.teams
+}
+
+func (m *KingOfTheHill) GetScores() []*Score {
+	if m.teams {
+		return teamScores(m.scores)
+	}
+	scores := make([]*Score, 0, len(m.scores))
+	for _, player := range m.world.GetPlayers() {
+		scores = append(scores, &Score{
+			ID:    player.GetID(),
+			Name:  player.Name,
+			Score: m.scores[player.GetID()],
+		})
+	}
+	return sortScores(scores)
+}
+
+func (m *KingOfTheHill) Reset() {
+	m.scores = make(map[string]int)
+	m.scoreTimer = conf.ZoneScoreInterval
+	m.rotateTimer = conf.ZoneRotationTime
+	m.activeZone = 0
+	m.activateZones()
+}
+
+func (m *KingOfTheHill) Update(deltaTime time.Duration) {
+	zones := m.world.GetZones()
+	if len(zones) > 1 && conf.ZoneRotationTime > 0 {
+		m.rotateTimer -= deltaTime
+		if m.rotateTimer <= 0 {
+			m.rotateTimer = conf.ZoneRotationTime
+			m.activeZone = (m.activeZone + 1) % len(zones)
+			m.activateZones()
+		}
+	}
+
+	m.scoreTimer -= deltaTime
+	score := m.scoreTimer <= 0
+	if score {
+		m.scoreTimer += conf.ZoneScoreInterval
+	}
+	for _, zone := range zones {
+		if owner := zone.update(m.teams, deltaTime); owner != "" && score {
+			m.scores[owner]++
+		}
+	}
+}
+
+// activateZones enables the current zone only when zones rotate, every zone
+// otherwise.
+func (m *KingOfTheHill) activateZones() {
+	rotate := conf.ZoneRotationTime > 0
+	for i, zone := range m.world.GetZones() {
+		zone.reset(!rotate || i == m.activeZone)
+	}
+}
+
+func (m *KingOfTheHill) OnJoin(player *Player) {
+	if m.teams {
+		player.Team = m.world.smallestTeam(m.scores)
+	}
+}
+
+func (m *KingOfTheHill) OnKill(victim, killer *Player) {
+}
+
+func (m *KingOfTheHill) CanRespawn(player *Player) bool {
+	return true
+}
+
+func (m *KingOfTheHill) IsOver() bool {
+	return false
+}
@@ -0,0 +1,244 @@
+package models
+
+import (
+	"github.com/g3n/engine/math32"
+)
+
+const defaultBounds = 100
+
+const (
+	ObstacleBox    = "box"
+	ObstacleSphere = "sphere"
+	ObstacleMesh   = "mesh"
+)
+
+// Level holds the static colliders of an arena. Bounds is the radius of the
+// playable sphere around the origin.
+type Level struct {
+	Bounds    float32
+	Obstacles []*Obstacle
+}
+
+// Obstacle is a static collider. Position is the center of the shape, Size
+// the full extents of a box and Radius the radius of a sphere. Convex meshes
+// are described by their Vertices, relative to Position, and triangle Faces
+// wound counter-clockwise when seen from outside.
+type Obstacle struct {
+	Kind     string
+	Position *math32.Vector3
+	Size     *math32.Vector3
+	Radius   float32
+	Vertices []math32.Vector3
+	Faces    [][3]int
+	Color    string
+
+	planes []*plane
+}
+
+type plane struct {
+	normal   *math32.Vector3
+	constant float32
+}
+
+func (p plane) distanceToPoint(point *math32.Vector3) float32 {
+	return p.normal.Dot(point) + p.constant
+}
+
+func NewBoxObstacle(position, size math32.Vector3, color string) *Obstacle {
+	return &Obstacle{
+		Kind:     ObstacleBox,
+		Position: &position,
+		Size:     &size,
+		Color:    color,
+	}
+}
+
+func NewSphereObstacle(position math32.Vector3, radius float32, color string) *Obstacle {
+	return &Obstacle{
+		Kind:     ObstacleSphere,
+		Position: &position,
+		Radius:   radius,
+		Color:    color,
+	}
+}
+
+func NewMeshObstacle(position math32.Vector3, vertices []math32.Vector3, faces [][3]int, color string) *Obstacle {
+	return &Obstacle{
+		Kind:     ObstacleMesh,
+		Position: &position,
+		Vertices: vertices,
+		Faces:    faces,
+		Color:    color,
+	}
+}
+
+func (l *Level) GetBounds() float32 {
+	if l == nil || l.Bounds <= 0 {
+		return defaultBounds
+	}
+	return l.Bounds
+}
+
+func (l *Level) OutOfBounds(point *math32.Vector3) bool {
+	return point.Length() > l.GetBounds()
+}
+
+func (l *Level) ContainsPoint(point *math32.Vector3) bool {
+	if l == nil {
+		return false
+	}
+	for _, obstacle := range l.Obstacles {
+		if obstacle.ContainsPoint(point) {
+			return true
+		}
+	}
+	return false
+}
+
+// PushOut moves the sphere out of every obstacle it overlaps. It returns the
+// normals of the surfaces that were hit.
+func (l *Level) PushOut(sphere *math32.Sphere) []*math32.Vector3 {
+	normals := make([]*math32.Vector3, 0)
+	if distance := sphere.Center.Length(); distance+sphere.Radius > l.GetBounds() {
+		normal := sphere.Center.Clone().Normalize().Negate()
+		sphere.Center.Add(normal.Clone().MultiplyScalar(distance + sphere.Radius - l.GetBounds()))
+		normals = append(normals, normal)
+	}
+	if l == nil {
+		return normals
+	}
+	for _, obstacle := range l.Obstacles {
+		if normal := obstacle.PushOut(sphere); normal != nil {
+			normals = append(normals, normal)
+		}
+	}
+	return normals
+}
+
+func (o *Obstacle) GetBox() *math32.Box3 {
+	half := o.Size.Clone().MultiplyScalar(0.5)
+	return math32.NewBox3(o.Position.Clone().Sub(half), o.Position.Clone().Add(half))
+}
+
+func (o *Obstacle) getPlanes() []*plane {
+	if o.planes != nil {
+		return o.planes
+	}
+	o.planes = make([]*plane, 0, len(o.Faces))
+	for _, face := range o.Faces {
+		a := o.Vertices[face[0]].Clone().Add(o.Position)
+		b := o.Vertices[face[1]].Clone().Add(o.Position)
+		c := o.Vertices[face[2]].Clone().Add(o.Position)
+		normal := c.Clone().Sub(b).Cross(a.Clone().Sub(b)).Normalize()
+		o.planes = append(o.planes, &plane{
+			normal:   normal,
+			constant: -normal.Dot(a),
+		})
+	}
+	return o.planes
+}
+
+func (o *Obstacle) ContainsPoint(point *math32.Vector3) bool {
+	switch o.Kind {
+	case ObstacleBox:
+		return o.GetBox().ContainsPoint(point)
+	case ObstacleSphere:
+		return math32.NewSphere(o.Position, o.Radius).ContainsPoint(point)
+	case ObstacleMesh:
+		for _, p := range o.getPlanes() {
+			if p.distanceToPoint(point) > 0 {
+				return false
+			}
+		}
+		return len(o.Faces) > 0
+	}
+	return false
+}
+
+// PushOut moves the sphere center out of the obstacle and returns the
+// surface normal, or nil when they do not overlap.
+func (o *Obstacle) PushOut(sphere *math32.Sphere) *math32.Vector3 {
+	switch o.Kind {
+	case ObstacleBox:
+		return o.pushOutOfBox(sphere)
+	case ObstacleSphere:
+		return o.pushOutOfSphere(sphere)
+	case ObstacleMesh:
+		return o.pushOutOfMesh(sphere)
+	}
+	return nil
+}
+
+func (o *Obstacle) pushOutOfBox(sphere *math32.Sphere) *math32.Vector3 {
+	box := o.GetBox()
+	closest := box.ClampPoint(&sphere.Center, nil)
+	normal := sphere.Center.Clone().Sub(closest)
+	distance := normal.Length()
+	if distance >= sphere.Radius {
+		return nil
+	}
+	if distance > 0 {
+		normal.Normalize()
+		sphere.Center.Add(normal.Clone().MultiplyScalar(sphere.Radius - distance))
+		return normal
+	}
+
+	// The center is inside the box: leave through the nearest face.
+	faces := []struct {
+		normal math32.Vector3
+		depth  float32
+	}{
+		{math32.Vector3{X: 1}, box.Max.X - sphere.Center.X},
+		{math32.Vector3{X: -1}, sphere.Center.X - box.Min.X},
+		{math32.Vector3{Y: 1}, box.Max.Y - sphere.Center.Y},
+		{math32.Vector3{Y: -1}, sphere.Center.Y - box.Min.Y},
+		{math32.Vector3{Z: 1}, box.Max.Z - sphere.Center.Z},
+		{math32.Vector3{Z: -1}, sphere.Center.Z - box.Min.Z},
+	}
+	nearest := faces[0]
+	for _, face := range faces[1:] {
+		if face.depth < nearest.depth {
+			nearest = face
+		}
+	}
+	normal = nearest.normal.Clone()
+	sphere.Center.Add(normal.Clone().MultiplyScalar(nearest.depth + sphere.Radius))
+	return normal
+}
+
+func (o *Obstacle) pushOutOfSphere(sphere *math32.Sphere) *math32.Vector3 {
+	normal := sphere.Center.Clone().Sub(o.Position)
+	distance := normal.Length()
+	if distance >= sphere.Radius+o.Radius {
+		return nil
+	}
+	if distance == 0 {
+		normal = &math32.Vector3{Y: 1}
+	}
+	normal.Normalize()
+	sphere.Center.Add(normal.Clone().MultiplyScalar(sphere.Radius + o.Radius - distance))
+	return normal
+}
+
+// pushOutOfMesh treats the mesh as the intersection of its face planes and
+// resolves along the face the sphere penetrates the least.
+func (o *Obstacle) pushOutOfMesh(sphere *math32.Sphere) *math32.Vector3 {
+	var nearest *plane
+	var nearestDistance float32
+	for _, p := range o.getPlanes() {
+		distance := p.distanceToPoint(&sphere.Center)
+		if distance >= sphere.Radius {
+			return nil
+		}
+		if nearest == nil || distance > nearestDistance {
+			nearest = p
+			nearestDistance = distance
+		}
+	}
+	if nearest == nil {
+		return nil
+	}
+	normal := nearest.normal.Clone()
+	sphere.Center.Add(normal.Clone().MultiplyScalar(sphere.Radius - nearestDistance))
+	return normal
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+const ModeLastManStanding = "lms"
+
+// SafeZone is the sphere players must stay in during a last man standing
+// round. It starts as the whole arena and, after conf.SafeZoneShrinkDelay,
+// shrinks toward a random spot over conf.SafeZoneShrinkTime.
+type SafeZone struct {
+	Center *math32.Vector3
+	Radius float32
+
+	startCenter *math32.Vector3
+	startRadius float32
+	endCenter   *math32.Vector3
+	endRadius   float32
+	elapsed     time.Duration
+}
+
+func newSafeZone(bounds float32) *SafeZone {
+	endRadius := float32(conf.SafeZoneMinRadius)
+	if endRadius > bounds {
+		endRadius = bounds
+	}
+	// Pick the final center so the final zone stays inside the first one.
+	offset := math32.NewVector3(rand.Float32()*2-1, rand.Float32()*2-1, rand.Float32()*2-1)
+	if offset.Length() > 1 {
+		offset.Normalize()
+	}
+	return &SafeZone{
+		Center:      math32.NewVec3(),
+		Radius:      bounds,
+		startCenter: math32.NewVec3(),
+		startRadius: bounds,
+		endCenter:   offset.MultiplyScalar(bounds - endRadius),
+		endRadius:   endRadius,
+	}
+}
+
+func (z *SafeZone) update(deltaTime time.Duration) {
+	z.elapsed += deltaTime
+	progress := float32(z.elapsed-conf.SafeZoneShrinkDelay) / float32(conf.SafeZoneShrinkTime)
+	progress = math32.Clamp(progress, 0, 1)
+	z.Center = z.startCenter.Clone().Lerp(z.endCenter, progress)
+	z.Radius = z.startRadius + (z.endRadius-z.startRadius)*progress
+}
+
+// Contains tells whether the point is inside the safe zone.
+func (z *SafeZone) Contains(point *math32.Vector3) bool {
+	return point.DistanceTo(z.Center) <= z.Radius
+}
+
+func (z *SafeZone) Refresh(zone SafeZone) {
+	z.Center = zone.Center
+	z.Radius = zone.Radius
+}
+
+// LastManStanding is an elimination mode: nobody respawns while the round
+// is running and the last survivor wins. A player's score is the number of
+// players eliminated before them, so survivors always lead.
+type LastManStanding struct {
+	roster     map[string]bool
+	placements map[string]int
+	eliminated int
+	damage     map[string]float32
+	world      *World
+}
+
+func NewLastManStanding(world *World) *LastManStanding {
+	mode := &LastManStanding{
+		world: world,
+	}
+	mode.Reset()
+	return mode
+}
+
+func (m *LastManStanding) GetName() string {
+	return ModeLastManStanding
+}
+
+func (m *LastManStanding) IsTeamMode() bool {
+	return false
+}
+
+func (m *LastManStanding) GetScores() []*Score {
+	scores := make([]*Score, 0)
+	for _, player := range m.world.GetPlayers() {
+		if !m.roster[player.GetID()] {
+			continue
+		}
+		score := m.eliminated
+		if placement, ok := m.placements[player.GetID()]; ok {
+			score = placement
+		}
+		scores = append(scores, &Score{
+			ID:    player.GetID(),
+			Name:  player.Name,
+			Score: score,
+		})
+	}
+	return sortScores(scores)
+}
+
+// Reset starts a new round with every connected player and a full size safe
+// zone.
+func (m *LastManStanding) Reset() {
+	m.roster = make(map[string]bool)
+	m.placements = make(map[string]int)
+	m.eliminated = 0
+	m.damage = make(map[string]float32)
+	for _, player := range m.world.GetPlayers() {
+		m.roster[player.GetID()] = true
+	}
+	m.world.SafeZone = newSafeZone(m.world.Level.GetBounds())
+}
+
+func (m *LastManStanding) Update(deltaTime time.Duration) {
+	m.world.SafeZone.update(deltaTime)
+
+	for _, player := range m.world.GetPlayers() {
+		if !m.roster[player.GetID()] {
+			// Players joining during a round wait for the next one.
+			if !player.Dead {
+				player.Kill(&DeathEvent{
+					Victim:     player.GetID(),
+					VictimName: player.Name,
+					Eliminated: true,
+				})
+			}
+			continue
+		}
+		if player.Dead || m.world.SafeZone.Contains(player.Position) {
+			continue
+		}
+		m.damage[player.GetID()] += conf.SafeZoneDamage * float32(deltaTime) / float32(time.Second)
+		if amount := int(m.damage[player.GetID()]); amount > 0 {
+			m.damage[player.GetID()] -= float32(amount)
+			player.takeDamage(amount, nil, WeaponZone)
+		}
+	}
+}
+
+func (m *LastManStanding) OnJoin(player *Player) {
+}
+
+func (m *LastManStanding) OnKill(victim, killer *Player) {
+	if _, ok := m.placements[victim.GetID()]; ok {
+		return
+	}
+	m.placements[victim.GetID()] = m.eliminated
+	m.eliminated++
+}
+
+func (m *LastManStanding) CanRespawn(player *Player) bool {
+	return !m.world.Match.IsRunning()
+}
+
+// IsOver ends the round once at most one player of the roster is alive.
+func (m *LastManStanding) IsOver() bool {
+	alive := 0
+	for _, player := range m.world.GetPlayers() {
+		if m.roster[player.GetID()] && !player.Dead {
+			alive++
+		}
+	}
+	return alive <= 1
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+const (
+	LightAmbient     = "ambient"
+	LightPoint       = "point"
+	LightDirectional = "directional"
+)
+
+// Map is an arena authored as a JSON file in conf.MapsDir. ID is the file
+// name without extension and Checksum the sha256 of the file content, used
+// to make sure the server and its clients load the same version. Gates are
+// the race checkpoints in order, the first one being the start line.
+type Map struct {
+	ID             string `json:"-"`
+	Checksum       string `json:"-"`
+	Name           string
+	Bounds         float32
+	Skybox         string
+	Lights         []*Light
+	Obstacles      []*Obstacle
+	SpawnPoints    []*math32.Vector3
+	Pickups        []*PickupSpawn
+	Flags          []*FlagBase
+	Zones          []*ZoneSpawn
+	Gates          []*Gate
+	DisableWeapons bool
+}
+
+type Light struct {
+	Kind      string
+	Color     *math32.Color
+	Intensity float32
+	Position  *math32.Vector3
+}
+
+type PickupSpawn struct {
+	Kind     string
+	Position *math32.Vector3
+}
+
+// MapInfo is what the server sends to identify its map during the handshake.
+type MapInfo struct {
+	ID       string
+	Checksum string
+}
+
+func LoadMap(id string) (*Map, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return nil, fmt.Errorf("invalid map id %q", id)
+	}
+
+	data, err := os.ReadFile(filepath.Join(conf.MapsDir, id+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var m Map
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("map %s: %v", id, err)
+	}
+
+	err = m.validate()
+	if err != nil {
+		return nil, fmt.Errorf("map %s: %v", id, err)
+	}
+
+	checksum := sha256.Sum256(data)
+	m.ID = id
+	m.Checksum = hex.EncodeToString(checksum[:])
+
+	return &m, nil
+}
+
+// validate rejects the maps that would make the server or the game panic or
+// silently ignore part of them.
+func (m *Map) validate() error {
+	if len(m.SpawnPoints) == 0 {
+		return fmt.Errorf("no spawn points")
+	}
+	for i, spawnPoint := range m.SpawnPoints {
+		if spawnPoint == nil {
+			return fmt.Errorf("spawn point %d has no position", i)
+		}
+	}
+
+	for i, l := range m.Lights {
+		switch l.Kind {
+		case LightAmbient:
+		case LightPoint, LightDirectional:
+			if l.Position == nil {
+				return fmt.Errorf("light %d has no position", i)
+			}
+		default:
+			return fmt.Errorf("light %d has unknown kind %q", i, l.Kind)
+		}
+		if l.Color == nil {
+			return fmt.Errorf("light %d has no color", i)
+		}
+	}
+
+	for i, obstacle := range m.Obstacles {
+		if err := obstacle.validate(); err != nil {
+			return fmt.Errorf("obstacle %d: %v", i, err)
+		}
+	}
+
+	for i, pickup := range m.Pickups {
+		if !isPickupKind(pickup.Kind) {
+			return fmt.Errorf("pickup %d has unknown kind %q", i, pickup.Kind)
+		}
+		if pickup.Position == nil {
+			return fmt.Errorf("pickup %d has no position", i)
+		}
+	}
+
+	for i, base := range m.Flags {
+		if !isTeam(base.Team) {
+			return fmt.Errorf("flag %d has unknown team %q", i, base.Team)
+		}
+		if base.Position == nil {
+			return fmt.Errorf("flag %d has no position", i)
+		}
+	}
+
+	for i, zone := range m.Zones {
+		if zone.Position == nil || zone.Radius <= 0 {
+			return fmt.Errorf("zone %d needs a position and a radius", i)
+		}
+	}
+
+	for i, gate := range m.Gates {
+		if gate.Position == nil || gate.Normal == nil || gate.Radius <= 0 {
+			return fmt.Errorf("gate %d needs a position, a normal and a radius", i)
+		}
+	}
+
+	return nil
+}
+
+func (o *Obstacle) validate() error {
+	if o.Position == nil {
+		return fmt.Errorf("no position")
+	}
+	switch o.Kind {
+	case ObstacleBox:
+		if o.Size == nil {
+			return fmt.Errorf("box has no size")
+		}
+	case ObstacleSphere:
+		if o.Radius <= 0 {
+			return fmt.Errorf("sphere has no radius")
+		}
+	case ObstacleMesh:
+		if len(o.Faces) == 0 {
+			return fmt.Errorf("mesh has no faces")
+		}
+		for i, face := range o.Faces {
+			for _, vertex := range face {
+				if vertex < 0 || vertex >= len(o.Vertices) {
+					return fmt.Errorf("face %d uses vertex %d of %d", i, vertex, len(o.Vertices))
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", o.Kind)
+	}
+	return nil
+}
+
+func (m *Map) GetInfo() MapInfo {
+	return MapInfo{
+		ID:       m.ID,
+		Checksum: m.Checksum,
+	}
+}
+
+func (m *Map) GetLevel() *Level {
+	return &Level{
+		Bounds:    m.Bounds,
+		Obstacles: m.Obstacles,
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lambher/video-game/conf"
+)
+
+const (
+	PhaseWarmup       = "warmup"
+	PhaseCountdown    = "countdown"
+	PhaseInProgress   = "in_progress"
+	PhaseOvertime     = "overtime"
+	PhaseIntermission = "intermission"
+)
+
+// MatchState is what clients need to display the match: its phase, the time
+// left in that phase, the standings and, once over, the winner.
+type MatchState struct {
+	Mode       string
+	Mutators   []string
+	Teams      bool
+	Phase      string
+	TimeLeft   time.Duration
+	ScoreLimit int
+	Scores     []*Score
+	Winner     string
+	WinnerName string
+}
+
+// Match drives the lifecycle of a match: warmup until enough players join,
+// a countdown, the match itself with an overtime when it ends in a tie, and
+// an intermission before everything is reset for the next one.
+type Match struct {
+	MatchState
+	TimeLimit  time.Duration
+	MinPlayers int
+
+	mode  Mode
+	world *World
+}
+
+func NewMatch(world *World, mode Mode, scoreLimit int, timeLimit time.Duration) *Match {
+	match := &Match{
+		TimeLimit:  timeLimit,
+		MinPlayers: conf.MinPlayers,
+		mode:       mode,
+		world:      world,
+	}
+	match.Mode = mode.GetName()
+	match.Teams = mode.IsTeamMode()
+	match.ScoreLimit = scoreLimit
+	match.Phase = PhaseWarmup
+	return match
+}
+
+func (m *Match) GetMode() Mode {
+	return m.mode
+}
+
+func (m *Match) GetState() *MatchState {
+	state := m.MatchState
+	state.Mutators = m.world.GetMutatorNames()
+	state.Scores = m.mode.GetScores()
+	return &state
+}
+
+// AllowsCombat tells whether players can fire in the current phase.
+func (m *Match) AllowsCombat() bool {
+	return m.Phase != PhaseCountdown && m.Phase != PhaseIntermission
+}
+
+// canRespawn tells whether the mode lets the dead player come back.
+func (m *Match) canRespawn(player *Player) bool {
+	return m == nil || m.mode.CanRespawn(player)
+}
+
+func (m *Match) IsRunning() bool {
+	return m.Phase == PhaseInProgress || m.Phase == PhaseOvertime
+}
+
+func (m *Match) Update(deltaTime time.Duration) {
+	if m.TimeLeft > 0 {
+		m.TimeLeft -= deltaTime
+	}
+
+	switch m.Phase {
+	case PhaseWarmup:
+		if len(m.world.GetPlayers()) >= m.MinPlayers {
+			m.setPhase(PhaseCountdown, conf.CountdownTime)
+		}
+	case PhaseCountdown:
+		if len(m.world.GetPlayers()) < m.MinPlayers {
+			m.setPhase(PhaseWarmup, 0)
+		} else if m.TimeLeft <= 0 {
+			m.reset()
+			m.setPhase(PhaseInProgress, m.TimeLimit)
+		}
+	case PhaseInProgress:
+		m.mode.Update(deltaTime)
+		scores := m.mode.GetScores()
+		if m.mode.IsOver() {
+			m.end(scores)
+		} else if m.ScoreLimit > 0 && len(scores) > 0 && scores[0].Score >= m.ScoreLimit {
+			m.end(scores)
+		} else if m.TimeLimit > 0 && m.TimeLeft <= 0 {
+			if isTied(scores) {
+				m.setPhase(PhaseOvertime, conf.OvertimeLimit)
+			} else {
+				m.end(scores)
+			}
+		}
+	case PhaseOvertime:
+		// Sudden death: the first to break the tie wins.
+		m.mode.Update(deltaTime)
+		scores := m.mode.GetScores()
+		if m.mode.IsOver() || !isTied(scores) || m.TimeLeft <= 0 {
+			m.end(scores)
+		}
+	case PhaseIntermission:
+		if m.TimeLeft <= 0 {
+			m.reset()
+			m.setPhase(PhaseWarmup, 0)
+		}
+	}
+}
+
+func (m *Match) end(scores []*Score) {
+	m.Winner = ""
+	m.WinnerName = ""
+	if len(scores) > 0 && !isTied(scores) {
+		m.Winner = scores[0].ID
+		m.WinnerName = scores[0].Name
+	}
+	m.setPhase(PhaseIntermission, conf.IntermissionTime)
+}
+
+// reset clears the stats and respawns every player for a new match.
+func (m *Match) reset() {
+	m.Winner = ""
+	m.WinnerName = ""
+	m.world.ResetStats()
+	m.mode.Reset()
+	for _, player := range m.world.GetPlayers() {
+		player.Respawn(m.world.ChooseSpawnPoint(player))
+	}
+}
+
+func (m *Match) setPhase(phase string, duration time.Duration) {
+	m.Phase = phase
+	m.TimeLeft = duration
+	m.world.events.Publish(m.GetState())
+}
+
+func isTied(scores []*Score) bool {
+	return len(scores) > 1 && scores[0].Score == scores[1].Score
+}
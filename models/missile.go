@@ -0,0 +1,175 @@
+package models
+
+import (
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/rs/xid"
+)
+
+const missileHP = 10
+const missileRadius = 0.4
+
+// Missile is a homing projectile. It steers toward its Target with a turn
+// rate limited by its weapon and can be shot down.
+type Missile struct {
+	ID       string
+	Player   *Player `json:"-"`
+	Owner    string
+	Target   string
+	Weapon   string
+	Damage   int
+	Position *math32.Vector3
+	Velocity *math32.Vector3
+
+	speed    float32
+	turnRate float32
+	fuel     time.Duration
+	hp       int
+	deleted  bool
+	world    *World
+}
+
+func NewMissile(world *World, player *Player, weapon *Weapon, direction *math32.Vector3) *Missile {
+	missile := &Missile{
+		ID:       xid.New().String(),
+		Player:   player,
+		Owner:    player.GetID(),
+		Weapon:   weapon.Name,
+		Damage:   weapon.Damage,
+		Position: player.Position.Clone().Add(player.Velocity),
+		Velocity: direction.Clone().Normalize().MultiplyScalar(weapon.ProjectileSpeed),
+		speed:    weapon.ProjectileSpeed,
+		turnRate: weapon.TurnRate,
+		fuel:     weapon.GetLifetime(),
+		hp:       missileHP,
+		world:    world,
+	}
+	if target := world.findLockTarget(player, direction, weapon); target != nil {
+		missile.Target = target.GetID()
+	}
+	return missile
+}
+
+// findLockTarget returns the living, visible enemy closest to the center of
+// the weapon lock cone.
+func (w *World) findLockTarget(shooter *Player, direction *math32.Vector3, weapon *Weapon) *Player {
+	var target *Player
+	bestAngle := weapon.LockCone
+	for _, player := range w.GetPlayers() {
+		if player == shooter || player.Dead || player.IsCloaked() || IsTeammate(player, shooter) {
+			continue
+		}
+		toPlayer := player.Position.Clone().Sub(shooter.Position)
+		if toPlayer.Length() > weapon.LockRange {
+			continue
+		}
+		if angle := direction.AngleTo(toPlayer); angle <= bestAngle {
+			target = player
+			bestAngle = angle
+		}
+	}
+	return target
+}
+
+func (m *Missile) Update(deltaTime time.Duration) {
+	m.steer()
+	m.Position.Add(m.Velocity)
+
+	m.fuel -= deltaTime
+	if m.fuel <= 0 {
+		m.detonate(m.Position, false)
+		return
+	}
+
+	for _, player := range m.world.players {
+		if player != m.Player && !player.Dead && player.GetHitBox().ContainsPoint(m.Position) {
+			m.detonate(m.Position, player.takeDamage(m.Damage, m.Player, m.Weapon))
+			return
+		}
+	}
+	for _, enemy := range m.world.GetEnemies() {
+		if !enemy.deleted && enemy.GetHitBox().ContainsPoint(m.Position) {
+			m.detonate(m.Position, enemy.takeDamage(m.Damage, m.Player, m.Weapon))
+			return
+		}
+	}
+	if m.world.Level.ContainsPoint(m.Position) {
+		m.detonate(m.Position.Clone().Sub(m.Velocity), false)
+		return
+	}
+	if m.world.Level.OutOfBounds(m.Position) {
+		m.deleted = true
+	}
+}
+
+// detonate removes the missile and explodes it. hit tells whether the
+// missile itself hurt someone.
+func (m *Missile) detonate(position *math32.Vector3, hit bool) {
+	if m.deleted {
+		return
+	}
+	m.deleted = true
+	if m.world.detonate(m.Player, m.Weapon, position) || hit {
+		m.world.recordHit(m.Player)
+	}
+}
+
+// steer turns the velocity toward the target by at most turnRate radians. A
+// missile loses track of a target that cloaks and flies straight.
+func (m *Missile) steer() {
+	target, ok := m.world.players[m.Target]
+	if !ok || target.Dead || target.IsCloaked() {
+		return
+	}
+	desired := target.Position.Clone().Sub(m.Position).Normalize()
+	current := m.Velocity.Clone().Normalize()
+	angle := current.AngleTo(desired)
+	if angle <= m.turnRate {
+		m.Velocity = desired.MultiplyScalar(m.speed)
+		return
+	}
+	axis := current.Clone().Cross(desired)
+	if axis.Length() == 0 && m.Player != nil {
+		axis = m.Player.Up.Clone()
+	}
+	if axis.Length() == 0 {
+		// The target is right behind and the owner is unknown, as when the
+		// missile reached a client before its owner: turn around any axis.
+		axis = current.Clone().Cross(&math32.Vector3{Y: 1})
+		if axis.Length() == 0 {
+			axis = &math32.Vector3{X: 1}
+		}
+	}
+	axis.Normalize()
+	m.Velocity = current.ApplyAxisAngle(axis, m.turnRate).MultiplyScalar(m.speed)
+}
+
+func (m *Missile) Hit(damage int) {
+	m.hp -= damage
+	if m.hp <= 0 {
+		m.detonate(m.Position, false)
+	}
+}
+
+func (m *Missile) Refresh(missile Missile) {
+	m.Target = missile.Target
+	m.Position = missile.Position
+	m.Velocity = missile.Velocity
+}
+
+func (m *Missile) UpdatePosition(deltaTime time.Duration) {
+	m.Position.Add(m.Velocity)
+}
+
+func (m Missile) GetHitBox() *math32.Sphere {
+	return math32.NewSphere(m.Position, missileRadius)
+}
+
+func (m Missile) IsDeleted() bool {
+	return m.deleted
+}
+
+func (m Missile) GetID() string {
+	return m.ID
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Mode holds the rules of a game mode. The match controller asks it for the
+// standings and lets it run its own logic while the match is in progress.
+type Mode interface {
+	GetName() string
+	IsTeamMode() bool
+	GetScores() []*Score
+	Reset()
+	Update(deltaTime time.Duration)
+	OnJoin(player *Player)
+	OnKill(victim, killer *Player)
+	CanRespawn(player *Player) bool
+	IsOver() bool
+}
+
+func NewMode(name string, world *World) (Mode, error) {
+	switch name {
+	case ModeFreeForAll:
+		return NewFreeForAll(world), nil
+	case ModeTeamDeathmatch:
+		return NewTeamDeathmatch(world), nil
+	case ModeCaptureTheFlag:
+		return NewCaptureTheFlag(world), nil
+	case ModeKingOfTheHill:
+		return NewKingOfTheHill(world, false), nil
+	case ModeTeamKingOfTheHill:
+		return NewKingOfTheHill(world, true), nil
+	case ModeLastManStanding:
+		return NewLastManStanding(world), nil
+	case ModeSurvival:
+		return NewSurvival(world), nil
+	case ModeRace:
+		return NewRace(world), nil
+	}
+	return nil, fmt.Errorf("unknown mode %q", name)
+}
+
+// Score is the standing of a player or a team.
+type Score struct {
+	ID    string
+	Name  string
+	Score int
+}
+
+func sortScores(scores []*Score) []*Score {
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+	return scores
+}
+
+const ModeFreeForAll = "ffa"
+
+// FreeForAll scores one point per kill.
+type FreeForAll struct {
+	world *World
+}
+
+func NewFreeForAll(world *World) *FreeForAll {
+	return &FreeForAll{
+		world: world,
+	}
+}
+
+func (m *FreeForAll) GetName() string {
+	return ModeFreeForAll
+}
+
+func (m *FreeForAll) IsTeamMode() bool {
+	return false
+}
+
+func (m *FreeForAll) GetScores() []*Score {
+	scores := make([]*Score, 0)
+	for _, stats := range m.world.GetScoreboard() {
+		scores = append(scores, &Score{
+			ID:    stats.Player,
+			Name:  stats.Name,
+			Score: stats.Kills,
+		})
+	}
+	return sortScores(scores)
+}
+
+func (m *FreeForAll) Reset() {
+}
+
+func (m *FreeForAll) Update(deltaTime time.Duration) {
+}
+
+func (m *FreeForAll) OnJoin(player *Player) {
+}
+
+func (m *FreeForAll) OnKill(victim, killer *Player) {
+}
+
+func (m *FreeForAll) CanRespawn(player *Player) bool {
+	return true
+}
+
+func (m *FreeForAll) IsOver() bool {
+	return false
+}
@@ -0,0 +1,239 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/lambher/video-game/conf"
+)
+
+const (
+	MutatorInstagib      = "instagib"
+	MutatorLowGravity    = "lowgravity"
+	MutatorHighKnockback = "highknockback"
+	MutatorUnlimitedAmmo = "unlimitedammo"
+	MutatorDoubleSpeed   = "doublespeed"
+	MutatorVampirism     = "vampirism"
+)
+
+// Mutator changes a rule of the game on top of any mode. Every hook gets
+// the value computed so far, so mutators stack in the order they were
+// added.
+type Mutator interface {
+	GetName() string
+	// ModifyDamage returns the damage the attacker, nil for the
+	// environment, deals with the weapon to the victim, a *Player or an
+	// *Enemy.
+	ModifyDamage(victim Model, attacker *Player, weapon string, amount int) int
+	// OnDamage runs once the victim has taken the damage.
+	OnDamage(victim Model, attacker *Player, amount int)
+	// ModifySpeed returns the flight speed of the player.
+	ModifySpeed(player *Player, speed float32) float32
+	// ModifyInertia returns the share of its velocity the player keeps from
+	// one tick to the next once the move keys are released.
+	ModifyInertia(player *Player, inertia float32) float32
+	// ModifyKnockback returns the push the target, a *Player or an *Enemy,
+	// gets from an explosion.
+	ModifyKnockback(target Model, knockback float32) float32
+	// OnFire runs after the player fired the weapon.
+	OnFire(player *Player, state *WeaponState)
+}
+
+func NewMutator(name string) (Mutator, error) {
+	switch name {
+	case MutatorInstagib:
+		return &Instagib{}, nil
+	case MutatorLowGravity:
+		return &LowGravity{}, nil
+	case MutatorHighKnockback:
+		return &HighKnockback{}, nil
+	case MutatorUnlimitedAmmo:
+		return &UnlimitedAmmo{}, nil
+	case MutatorDoubleSpeed:
+		return &DoubleSpeed{}, nil
+	case MutatorVampirism:
+		return &Vampirism{}, nil
+	}
+	return nil, fmt.Errorf("unknown mutator %q", name)
+}
+
+// mutator leaves every rule unchanged. Mutators embed it and only override
+// the hooks they need.
+type mutator struct{}
+
+func (mutator) ModifyDamage(victim Model, attacker *Player, weapon string, amount int) int {
+	return amount
+}
+
+func (mutator) OnDamage(victim Model, attacker *Player, amount int) {
+}
+
+func (mutator) ModifySpeed(player *Player, speed float32) float32 {
+	return speed
+}
+
+func (mutator) ModifyInertia(player *Player, inertia float32) float32 {
+	return inertia
+}
+
+func (mutator) ModifyKnockback(target Model, knockback float32) float32 {
+	return knockback
+}
+
+func (mutator) OnFire(player *Player, state *WeaponState) {
+}
+
+// Instagib makes any weapon hit from another player lethal, shield included.
+// Ramming keeps its normal damage, or every collision would kill both ships.
+type Instagib struct {
+	mutator
+}
+
+func (m *Instagib) GetName() string {
+	return MutatorInstagib
+}
+
+func (m *Instagib) ModifyDamage(victim Model, attacker *Player, weapon string, amount int) int {
+	if attacker == nil || victim == attacker || weapon == WeaponRam || amount <= 0 {
+		return amount
+	}
+	switch victim := victim.(type) {
+	case *Player:
+		return victim.hp + victim.Shield
+	case *Enemy:
+		return victim.HP
+	}
+	return amount
+}
+
+// LowGravity is the floaty feel of low gravity for ships that have no
+// gravity to begin with: they keep conf.LowGravityInertia of their velocity
+// each tick and drift much further once the keys are released.
+type LowGravity struct {
+	mutator
+}
+
+func (m *LowGravity) GetName() string {
+	return MutatorLowGravity
+}
+
+func (m *LowGravity) ModifyInertia(player *Player, inertia float32) float32 {
+	return conf.LowGravityInertia
+}
+
+// HighKnockback lets explosions push ships and enemies conf.HighKnockback
+// times further.
+type HighKnockback struct {
+	mutator
+}
+
+func (m *HighKnockback) GetName() string {
+	return MutatorHighKnockback
+}
+
+func (m *HighKnockback) ModifyKnockback(target Model, knockback float32) float32 {
+	return knockback * conf.HighKnockback
+}
+
+// UnlimitedAmmo gives back every round fired, so weapons never reload.
+type UnlimitedAmmo struct {
+	mutator
+}
+
+func (m *UnlimitedAmmo) GetName() string {
+	return MutatorUnlimitedAmmo
+}
+
+func (m *UnlimitedAmmo) OnFire(player *Player, state *WeaponState) {
+	state.Ammo++
+}
+
+// DoubleSpeed doubles the flight speed of every ship.
+type DoubleSpeed struct {
+	mutator
+}
+
+func (m *DoubleSpeed) GetName() string {
+	return MutatorDoubleSpeed
+}
+
+func (m *DoubleSpeed) ModifySpeed(player *Player, speed float32) float32 {
+	return speed * 2
+}
+
+// Vampirism heals attackers by conf.VampirismRatio of the damage they deal
+// to other players and to enemies.
+type Vampirism struct {
+	mutator
+}
+
+func (m *Vampirism) GetName() string {
+	return MutatorVampirism
+}
+
+func (m *Vampirism) OnDamage(victim Model, attacker *Player, amount int) {
+	if attacker == nil || victim == attacker || attacker.Dead {
+		return
+	}
+	heal := int(float32(amount) * conf.VampirismRatio)
+	if heal <= 0 || attacker.hp >= maxHP {
+		return
+	}
+	hp := attacker.hp + heal
+	if hp > maxHP {
+		hp = maxHP
+	}
+	attacker.SetHP(hp)
+}
+
+func (w *World) AddMutator(mutator Mutator) {
+	w.Mutators = append(w.Mutators, mutator)
+}
+
+// GetMutatorNames returns the names of the active mutators in order.
+func (w *World) GetMutatorNames() []string {
+	names := make([]string, 0, len(w.Mutators))
+	for _, mutator := range w.Mutators {
+		names = append(names, mutator.GetName())
+	}
+	return names
+}
+
+func (w *World) modifyDamage(victim Model, attacker *Player, weapon string, amount int) int {
+	for _, mutator := range w.Mutators {
+		amount = mutator.ModifyDamage(victim, attacker, weapon, amount)
+	}
+	return amount
+}
+
+func (w *World) onDamage(victim Model, attacker *Player, amount int) {
+	for _, mutator := range w.Mutators {
+		mutator.OnDamage(victim, attacker, amount)
+	}
+}
+
+func (w *World) modifySpeed(player *Player, speed float32) float32 {
+	for _, mutator := range w.Mutators {
+		speed = mutator.ModifySpeed(player, speed)
+	}
+	return speed
+}
+
+func (w *World) modifyInertia(player *Player, inertia float32) float32 {
+	for _, mutator := range w.Mutators {
+		inertia = mutator.ModifyInertia(player, inertia)
+	}
+	return inertia
+}
+
+func (w *World) modifyKnockback(target Model, knockback float32) float32 {
+	for _, mutator := range w.Mutators {
+		knockback = mutator.ModifyKnockback(target, knockback)
+	}
+	return knockback
+}
+
+func (w *World) onFire(player *Player, state *WeaponState) {
+	for _, mutator := range w.Mutators {
+		mutator.OnFire(player, state)
+	}
+}
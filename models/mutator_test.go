@@ -0,0 +1,80 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/g3n/engine/math32"
+)
+
+func newTestEnemy(world *World, hp int) *Enemy {
+	return &Enemy{
+		ID:        "enemy",
+		Position:  math32.NewVector3(0, 0, -10),
+		Velocity:  math32.NewVec3(),
+		Direction: math32.NewVector3(0, 0, 1),
+		HP:        hp,
+		MaxHP:     hp,
+		world:     world,
+	}
+}
+
+func TestMutatorsOnEnemies(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutator  Mutator
+		enemyHP  int
+		playerHP int
+	}{
+		{name: "none", mutator: nil, enemyHP: 90, playerHP: 50},
+		{name: "instagib", mutator: &Instagib{}, enemyHP: 0, playerHP: 50},
+		{name: "vampirism", mutator: &Vampirism{}, enemyHP: 90, playerHP: 55},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			world := &World{}
+			if test.mutator != nil {
+				world.AddMutator(test.mutator)
+			}
+			player := NewPlayer("player", world, "player", math32.Vector3{})
+			world.AddPlayer(player)
+			player.hp = 50
+			enemy := newTestEnemy(world, 100)
+
+			if !enemy.takeDamage(10, player, "blaster") {
+				t.Fatal("enemy did not take the hit")
+			}
+			if enemy.HP != test.enemyHP {
+				t.Errorf("enemy hp = %d, want %d", enemy.HP, test.enemyHP)
+			}
+			if player.GetHP() != test.playerHP {
+				t.Errorf("player hp = %d, want %d", player.GetHP(), test.playerHP)
+			}
+		})
+	}
+}
+
+func TestLowGravityDrift(t *testing.T) {
+	drift := func(mutators ...Mutator) float32 {
+		world := &World{}
+		for _, mutator := range mutators {
+			world.AddMutator(mutator)
+		}
+		player := NewPlayer("player", world, "player", math32.Vector3{})
+		world.AddPlayer(player)
+		player.MoveForward(true)
+		player.Update(16 * time.Millisecond)
+		player.MoveForward(false)
+		start := player.Position.Clone()
+		for i := 0; i < 60; i++ {
+			player.Update(16 * time.Millisecond)
+		}
+		return player.Position.DistanceTo(start)
+	}
+
+	normal := drift()
+	low := drift(&LowGravity{})
+	if low < normal*2 {
+		t.Errorf("low gravity drift = %v, want at least twice %v", low, normal)
+	}
+}
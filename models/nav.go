@@ -0,0 +1,271 @@
+package models
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+// NavGraph is a waypoint graph of the free space of a map, used by bots to
+// fly around obstacles. It is built offline by navgen from the static
+// colliders and stored next to the map as <id>.nav.json. Checksum is the one
+// of the map it was built from, so a stale graph is refused.
+type NavGraph struct {
+	Map      string
+	Checksum string
+	Nodes    []*math32.Vector3
+	Edges    [][2]int
+
+	level     *Level
+	neighbors [][]int
+}
+
+// BuildNavGraph samples the playable sphere of the map on a grid of
+// conf.NavSpacing, keeps the points where a player fits and links each point
+// to the free points around it, diagonals included.
+func BuildNavGraph(m *Map) *NavGraph {
+	level := m.GetLevel()
+	graph := &NavGraph{
+		Map:      m.ID,
+		Checksum: m.Checksum,
+		Nodes:    make([]*math32.Vector3, 0),
+		Edges:    make([][2]int, 0),
+		level:    level,
+	}
+
+	steps := int(level.GetBounds() / conf.NavSpacing)
+	index := make(map[[3]int]int)
+	cells := make([][3]int, 0)
+	for x := -steps; x <= steps; x++ {
+		for y := -steps; y <= steps; y++ {
+			for z := -steps; z <= steps; z++ {
+				point := math32.NewVector3(float32(x), float32(y), float32(z)).MultiplyScalar(conf.NavSpacing)
+				if !graph.isFree(point) {
+					continue
+				}
+				index[[3]int{x, y, z}] = len(graph.Nodes)
+				cells = append(cells, [3]int{x, y, z})
+				graph.Nodes = append(graph.Nodes, point)
+			}
+		}
+	}
+
+	for i, cell := range cells {
+		for dx := 0; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				for dz := -1; dz <= 1; dz++ {
+					// Only half of the neighbors, the other half links back
+					// to this node.
+					if dx == 0 && (dy < 0 || (dy == 0 && dz <= 0)) {
+						continue
+					}
+					j, ok := index[[3]int{cell[0] + dx, cell[1] + dy, cell[2] + dz}]
+					if ok && graph.IsClear(graph.Nodes[i], graph.Nodes[j]) {
+						graph.Edges = append(graph.Edges, [2]int{i, j})
+					}
+				}
+			}
+		}
+	}
+
+	graph.link()
+	return graph
+}
+
+func navPath(id string) string {
+	return filepath.Join(conf.MapsDir, id+".nav.json")
+}
+
+// LoadNavGraph reads the navigation graph stored next to the map.
+func LoadNavGraph(m *Map) (*NavGraph, error) {
+	data, err := os.ReadFile(navPath(m.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	var graph NavGraph
+	err = json.Unmarshal(data, &graph)
+	if err != nil {
+		return nil, fmt.Errorf("navigation graph %s: %v", m.ID, err)
+	}
+	if graph.Checksum != m.Checksum {
+		return nil, fmt.Errorf("navigation graph %s is out of date, run navgen", m.ID)
+	}
+	for _, edge := range graph.Edges {
+		if edge[0] < 0 || edge[0] >= len(graph.Nodes) || edge[1] < 0 || edge[1] >= len(graph.Nodes) {
+			return nil, fmt.Errorf("navigation graph %s: invalid edge %v", m.ID, edge)
+		}
+	}
+
+	graph.level = m.GetLevel()
+	graph.link()
+	return &graph, nil
+}
+
+func (g *NavGraph) Save() error {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(navPath(g.Map), data, 0644)
+}
+
+func (g *NavGraph) link() {
+	g.neighbors = make([][]int, len(g.Nodes))
+	for _, edge := range g.Edges {
+		g.neighbors[edge[0]] = append(g.neighbors[edge[0]], edge[1])
+		g.neighbors[edge[1]] = append(g.neighbors[edge[1]], edge[0])
+	}
+}
+
+// isFree tells whether a sphere of conf.NavClearance fits at the point.
+func (g *NavGraph) isFree(point *math32.Vector3) bool {
+	sphere := math32.NewSphere(point.Clone(), conf.NavClearance)
+	return len(g.level.PushOut(sphere)) == 0
+}
+
+// IsClear tells whether a player can fly in a straight line between the
+// two points without touching an obstacle.
+func (g *NavGraph) IsClear(from, to *math32.Vector3) bool {
+	direction := to.Clone().Sub(from)
+	distance := direction.Length()
+	if distance == 0 {
+		return g.isFree(from)
+	}
+	if _, _, blocked := g.level.Raycast(from, direction.Clone().Normalize(), distance); blocked {
+		return false
+	}
+	steps := int(math32.Ceil(distance / conf.NavClearance))
+	for i := 0; i <= steps; i++ {
+		if !g.isFree(from.Clone().Lerp(to, float32(i)/float32(steps))) {
+			return false
+		}
+	}
+	return true
+}
+
+// nearest returns the closest node in a straight line of the point, or -1.
+func (g *NavGraph) nearest(point *math32.Vector3) int {
+	nodes := make([]int, len(g.Nodes))
+	for i := range g.Nodes {
+		nodes[i] = i
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return g.Nodes[nodes[i]].DistanceToSquared(point) < g.Nodes[nodes[j]].DistanceToSquared(point)
+	})
+	for _, node := range nodes {
+		if g.IsClear(point, g.Nodes[node]) {
+			return node
+		}
+	}
+	return -1
+}
+
+// FindPath returns the waypoints to fly through to go from one point to
+// the other, the last one being to, or nil when there is no path.
+func (g *NavGraph) FindPath(from, to *math32.Vector3) []*math32.Vector3 {
+	if g.IsClear(from, to) {
+		return []*math32.Vector3{to.Clone()}
+	}
+	start := g.nearest(from)
+	goal := g.nearest(to)
+	if start < 0 || goal < 0 {
+		return nil
+	}
+	nodes := g.search(start, goal)
+	if nodes == nil {
+		return nil
+	}
+
+	path := make([]*math32.Vector3, 0, len(nodes)+1)
+	for _, node := range nodes {
+		path = append(path, g.Nodes[node].Clone())
+	}
+	path = append(path, to.Clone())
+	return g.smooth(from, path)
+}
+
+// search is an A* from start to goal, returning the node indices of the path.
+func (g *NavGraph) search(start, goal int) []int {
+	cost := map[int]float32{start: 0}
+	cameFrom := make(map[int]int)
+	closed := make(map[int]bool)
+	open := &navQueue{{node: start, priority: g.Nodes[start].DistanceTo(g.Nodes[goal])}}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(navItem).node
+		if current == goal {
+			nodes := []int{goal}
+			for current != start {
+				current = cameFrom[current]
+				nodes = append([]int{current}, nodes...)
+			}
+			return nodes
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+
+		for _, next := range g.neighbors[current] {
+			if closed[next] {
+				continue
+			}
+			nextCost := cost[current] + g.Nodes[current].DistanceTo(g.Nodes[next])
+			if known, ok := cost[next]; ok && known <= nextCost {
+				continue
+			}
+			cost[next] = nextCost
+			cameFrom[next] = current
+			heap.Push(open, navItem{
+				node:     next,
+				priority: nextCost + g.Nodes[next].DistanceTo(g.Nodes[goal]),
+			})
+		}
+	}
+	return nil
+}
+
+// smooth drops the waypoints that can be skipped in a straight line, so
+// bots do not zigzag along the grid.
+func (g *NavGraph) smooth(from *math32.Vector3, path []*math32.Vector3) []*math32.Vector3 {
+	smoothed := make([]*math32.Vector3, 0, len(path))
+	position := from
+	for i := 0; i < len(path); {
+		next := i
+		for j := len(path) - 1; j > i; j-- {
+			if g.IsClear(position, path[j]) {
+				next = j
+				break
+			}
+		}
+		smoothed = append(smoothed, path[next])
+		position = path[next]
+		i = next + 1
+	}
+	return smoothed
+}
+
+type navItem struct {
+	node     int
+	priority float32
+}
+
+type navQueue []navItem
+
+func (q navQueue) Len() int            { return len(q) }
+func (q navQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q navQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *navQueue) Push(x interface{}) { *q = append(*q, x.(navItem)) }
+func (q *navQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
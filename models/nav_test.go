@@ -0,0 +1,172 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+// enclosure returns the six walls of a hollow cube around center, inner
+// is the half size of the room inside.
+func enclosure(center math32.Vector3, inner float32) []*Obstacle {
+	walls := make([]*Obstacle, 0, 6)
+	for axis := 0; axis < 3; axis++ {
+		for _, side := range []float32{-1, 1} {
+			offset := math32.Vector3{}
+			offset.SetComponent(axis, side*(inner+0.5))
+			size := math32.Vector3{X: inner*2 + 2, Y: inner*2 + 2, Z: inner*2 + 2}
+			size.SetComponent(axis, 1)
+			walls = append(walls, NewBoxObstacle(*center.Clone().Add(&offset), size, ""))
+		}
+	}
+	return walls
+}
+
+func TestFindPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		obstacles []*Obstacle
+		from, to  math32.Vector3
+		found     bool
+	}{
+		{
+			name:      "open space",
+			obstacles: nil,
+			from:      math32.Vector3{X: -15},
+			to:        math32.Vector3{X: 15},
+			found:     true,
+		},
+		{
+			name:      "around a box",
+			obstacles: []*Obstacle{NewBoxObstacle(math32.Vector3{}, math32.Vector3{X: 6, Y: 6, Z: 6}, "")},
+			from:      math32.Vector3{X: -15},
+			to:        math32.Vector3{X: 15},
+			found:     true,
+		},
+		{
+			name:      "enclosed goal",
+			obstacles: enclosure(math32.Vector3{X: 10}, 4),
+			from:      math32.Vector3{X: -15},
+			to:        math32.Vector3{X: 10},
+			found:     false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			graph := BuildNavGraph(&Map{ID: "test", Bounds: 20, Obstacles: test.obstacles})
+			path := graph.FindPath(&test.from, &test.to)
+
+			if !test.found {
+				if path != nil {
+					t.Fatalf("path = %v, want nil", path)
+				}
+				return
+			}
+			if len(path) == 0 {
+				t.Fatal("no path found")
+			}
+			if !path[len(path)-1].Equals(&test.to) {
+				t.Errorf("path ends at %v, want %v", path[len(path)-1], test.to)
+			}
+			position := &test.from
+			for _, waypoint := range path {
+				if !graph.IsClear(position, waypoint) {
+					t.Errorf("path goes through an obstacle from %v to %v", position, waypoint)
+				}
+				position = waypoint
+			}
+		})
+	}
+}
+
+func TestSmooth(t *testing.T) {
+	graph := BuildNavGraph(&Map{ID: "test", Bounds: 20})
+	from := math32.NewVector3(-15, 0, 0)
+	path := []*math32.Vector3{
+		math32.NewVector3(-10, 10, 0),
+		math32.NewVector3(0, 0, 0),
+		math32.NewVector3(10, -10, 0),
+		math32.NewVector3(15, 0, 0),
+	}
+
+	smoothed := graph.smooth(from, path)
+	if len(smoothed) != 1 || smoothed[0] != path[3] {
+		t.Errorf("smoothed = %v, want only %v", smoothed, path[3])
+	}
+}
+
+func TestSearch(t *testing.T) {
+	graph := BuildNavGraph(&Map{
+		ID:        "test",
+		Bounds:    20,
+		Obstacles: []*Obstacle{NewBoxObstacle(math32.Vector3{}, math32.Vector3{X: 6, Y: 6, Z: 6}, "")},
+	})
+	start := graph.nearest(math32.NewVector3(-10, 0, 0))
+	goal := graph.nearest(math32.NewVector3(10, 0, 0))
+
+	nodes := graph.search(start, goal)
+	if len(nodes) < 3 || nodes[0] != start || nodes[len(nodes)-1] != goal {
+		t.Fatalf("search = %v, want a path from %d to %d around the box", nodes, start, goal)
+	}
+	for i := 1; i < len(nodes); i++ {
+		if !graph.IsClear(graph.Nodes[nodes[i-1]], graph.Nodes[nodes[i]]) {
+			t.Errorf("nodes %d and %d are not linked", nodes[i-1], nodes[i])
+		}
+	}
+}
+
+func TestLoadNavGraph(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(dir)
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(filepath.Clean(conf.MapsDir), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		checksum string
+		edges    [][2]int
+		valid    bool
+	}{
+		{name: "valid", checksum: "map", edges: [][2]int{{0, 1}}, valid: true},
+		{name: "out of date", checksum: "old", edges: [][2]int{{0, 1}}, valid: false},
+		{name: "edge out of range", checksum: "map", edges: [][2]int{{0, 2}}, valid: false},
+		{name: "negative edge", checksum: "map", edges: [][2]int{{-1, 1}}, valid: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			graph := &NavGraph{
+				Map:      "test",
+				Checksum: test.checksum,
+				Nodes:    []*math32.Vector3{math32.NewVector3(0, 0, 0), math32.NewVector3(10, 0, 0)},
+				Edges:    test.edges,
+			}
+			err := graph.Save()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := LoadNavGraph(&Map{ID: "test", Checksum: "map", Bounds: 20})
+			if test.valid && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("invalid graph loaded")
+			}
+			if test.valid && err == nil && len(loaded.neighbors[0]) != 1 {
+				t.Errorf("neighbors of node 0 = %v, want [1]", loaded.neighbors[0])
+			}
+		})
+	}
+}
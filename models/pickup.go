@@ -0,0 +1,127 @@
+package models
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+	"github.com/rs/xid"
+)
+
+const (
+	PickupHealth = "health"
+	PickupAmmo   = "ammo"
+	PickupShield = "shield"
+	PickupSpeed  = "speed"
+	PickupDamage = "damage"
+	PickupCloak  = "cloak"
+)
+
+var PickupKinds = []string{PickupHealth, PickupAmmo, PickupShield, PickupSpeed, PickupDamage, PickupCloak}
+
+func isPickupKind(kind string) bool {
+	for _, k := range PickupKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+const pickupRadius = 1
+
+// Pickup is an item lying in the arena. Once collected it stays inactive
+// until its respawn timer runs out.
+type Pickup struct {
+	ID       string
+	Kind     string
+	Position *math32.Vector3
+	Active   bool
+
+	respawnTimer time.Duration
+	world        *World
+}
+
+type PickupEvent struct {
+	Pickup string
+	Kind   string
+	Player string
+	Active bool
+}
+
+func NewPickup(world *World, kind string, position math32.Vector3) *Pickup {
+	return &Pickup{
+		ID:       xid.New().String(),
+		Kind:     kind,
+		Position: &position,
+		Active:   true,
+		world:    world,
+	}
+}
+
+// NewRandomPickup places a pickup of a random kind at a random free spot
+// inside the arena.
+func NewRandomPickup(world *World) *Pickup {
+	bounds := world.Level.GetBounds() / 2
+	position := math32.Vector3{}
+	for i := 0; i < 10; i++ {
+		position = math32.Vector3{
+			X: (rand.Float32()*2 - 1) * bounds,
+			Y: (rand.Float32()*2 - 1) * bounds,
+			Z: (rand.Float32()*2 - 1) * bounds,
+		}
+		sphere := math32.NewSphere(&position, pickupRadius)
+		if len(world.Level.PushOut(sphere)) == 0 {
+			break
+		}
+	}
+	return NewPickup(world, PickupKinds[rand.Intn(len(PickupKinds))], position)
+}
+
+func (p *Pickup) Update(deltaTime time.Duration) {
+	if !p.Active {
+		p.respawnTimer -= deltaTime
+		if p.respawnTimer <= 0 {
+			p.SetActive(true, nil)
+		}
+		return
+	}
+
+	for _, player := range p.world.players {
+		if player.Dead || player.Position.DistanceTo(p.Position) > player.GetHitBox().Radius+pickupRadius {
+			continue
+		}
+		if player.collect(p.Kind) {
+			p.respawnTimer = conf.PickupRespawnTime
+			p.SetActive(false, player)
+			return
+		}
+	}
+}
+
+// SetActive shows or hides the pickup. The player is the one who collected
+// it, if any.
+func (p *Pickup) SetActive(active bool, player *Player) {
+	p.Active = active
+	event := &PickupEvent{
+		Pickup: p.ID,
+		Kind:   p.Kind,
+		Active: active,
+	}
+	if player != nil {
+		event.Player = player.GetID()
+	}
+	p.world.events.Publish(event)
+}
+
+func (p *Pickup) UpdatePosition(deltaTime time.Duration) {
+}
+
+func (p Pickup) IsDeleted() bool {
+	return false
+}
+
+func (p Pickup) GetID() string {
+	return p.ID
+}
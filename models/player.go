@@ -1,10 +1,10 @@
 package models
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
 )
 
 const (
@@ -27,13 +27,28 @@ type Player struct {
 	VerticalAngle   float32
 	HorizontalAngle float32
 	Name            string
+	Team            string
+	Weapon          string
+	Dead            bool
+	Invulnerable    bool
+	Shield          int
+	Effects         map[string]time.Duration
 	hp              int
 	deleted         bool
 
+	respawnTimer      time.Duration
+	invulnerableTimer time.Duration
+	arsenal           map[string]*WeaponState
+	rejectedShots     int
+	damagers          map[string]*Player
+	knockback         *math32.Vector3
+
 	moves *Moves
 	world *World
 }
 
+const maxHP = 100
+
 type Moves struct {
 	Keys                    map[string]bool
 	VerticalAngleAngleSpeed float32
@@ -66,13 +81,15 @@ func NewPlayer(id string, world *World, name string, position math32.Vector3) *P
 		},
 		Velocity:        math32.NewVec3(),
 		Name:            name,
+		Weapon:          world.GetDefaultWeapon(),
 		VerticalAngle:   0,
 		HorizontalAngle: 0,
 
-		hp:      100,
+		hp:      maxHP,
 		deleted: false,
 	}
 	player.moves = newMoves()
+	player.knockback = math32.NewVec3()
 	player.world = world
 
 	return player
@@ -153,17 +170,18 @@ func (p Player) GetLookAt() *math32.Vector3 {
 }
 
 func (p *Player) updateMoves() {
+	speed := p.world.modifySpeed(p, 0.1*p.speedMultiplier())
 	if p.moves.Keys[MoveForward] {
-		p.Velocity = p.Direction.Clone().MultiplyScalar(0.1)
+		p.Velocity = p.Direction.Clone().MultiplyScalar(speed)
 	}
 	if p.moves.Keys[MoveBackward] {
-		p.Velocity = p.Direction.Clone().MultiplyScalar(-0.1)
+		p.Velocity = p.Direction.Clone().MultiplyScalar(-speed)
 	}
 	if p.moves.Keys[MoveLeft] {
-		p.Velocity = p.GetLeftAxis().MultiplyScalar(-0.1)
+		p.Velocity = p.GetLeftAxis().MultiplyScalar(-speed)
 	}
 	if p.moves.Keys[MoveRight] {
-		p.Velocity = p.GetLeftAxis().MultiplyScalar(0.1)
+		p.Velocity = p.GetLeftAxis().MultiplyScalar(speed)
 	}
 	if p.moves.Keys[TurnLeft] {
 		p.VerticalAngle = p.moves.VerticalAngleAngleSpeed
@@ -179,15 +197,189 @@ func (p *Player) updateMoves() {
 	}
 }
 
-func (p *Player) Fire() {
-	bullet := NewBullet(p.world, p, p.Direction.Clone().MultiplyScalar(.5).Add(p.Velocity))
-	p.world.AddBullet(bullet)
+func (p *Player) GetWeapon() *Weapon {
+	return p.world.GetWeapon(p.Weapon)
+}
+
+func (p *Player) SwitchWeapon(name string) bool {
+	if p.world.GetWeapon(name) == nil {
+		return false
+	}
+	if name == p.Weapon {
+		return true
+	}
+	if state := p.GetWeaponState(); state != nil && state.Reloading {
+		state.Reloading = false
+		p.onWeaponState(state)
+	}
+	p.Weapon = name
+	if state := p.GetWeaponState(); state != nil {
+		p.onWeaponState(state)
+	}
+	return true
+}
+
+// GetWeaponState returns the ammunition of the current weapon.
+func (p *Player) GetWeaponState() *WeaponState {
+	weapon := p.GetWeapon()
+	if weapon == nil {
+		return nil
+	}
+	return p.getWeaponState(weapon)
+}
+
+func (p *Player) getWeaponState(weapon *Weapon) *WeaponState {
+	if p.arsenal == nil {
+		p.arsenal = make(map[string]*WeaponState)
+	}
+	if _, ok := p.arsenal[weapon.Name]; !ok {
+		p.arsenal[weapon.Name] = newWeaponState(weapon)
+	}
+	return p.arsenal[weapon.Name]
+}
+
+// SetWeaponState replaces the ammunition of a weapon. Clients call it with
+// the state received from the server.
+func (p *Player) SetWeaponState(state *WeaponState) {
+	if p.arsenal == nil {
+		p.arsenal = make(map[string]*WeaponState)
+	}
+	p.arsenal[state.Weapon] = state
+	p.onWeaponState(state)
+}
+
+func (p *Player) GetRejectedShots() int {
+	return p.rejectedShots
+}
+
+// Fire shoots the current weapon if its cooldown, reload and ammunition
+// allow it. Rejected shots are counted and returned as an error.
+func (p *Player) Fire() error {
+	err := p.fire()
+	if err != nil && err != ErrDead && err != ErrNoCombat && err != ErrNoWeapons {
+		p.rejectedShots++
+	}
+	return err
+}
+
+func (p *Player) fire() error {
+	if p.Dead {
+		return ErrDead
+	}
+	if p.world.NoWeapons {
+		return ErrNoWeapons
+	}
+	if p.world.Match != nil && !p.world.Match.AllowsCombat() {
+		return ErrNoCombat
+	}
+	weapon := p.GetWeapon()
+	if weapon == nil {
+		return ErrNoWeapon
+	}
+	state := p.GetWeaponState()
+	if err := state.canFire(); err != nil {
+		if err == ErrNoAmmo && state.startReload(weapon) {
+			p.onWeaponState(state)
+		}
+		return err
+	}
+
+	state.Ammo--
+	state.cooldown = weapon.GetCooldown()
+	p.world.onFire(p, state)
+	for i := 0; i < weapon.ProjectileCount; i++ {
+		p.world.recordShot(p)
+		direction := weapon.spread(p.Direction, p.Up)
+		if weapon.Hitscan {
+			p.fireHitscan(weapon, direction)
+			continue
+		}
+		if weapon.Projectile == ProjectileMissile {
+			p.world.AddMissile(NewMissile(p.world, p, weapon, direction))
+			continue
+		}
+		velocity := direction.MultiplyScalar(weapon.ProjectileSpeed).Add(p.Velocity)
+		p.world.AddBullet(NewBullet(p.world, p, weapon, velocity))
+	}
+	if state.Ammo == 0 {
+		state.startReload(weapon)
+	}
+	p.onWeaponState(state)
+
+	return nil
+}
+
+func (p *Player) fireHitscan(weapon *Weapon, direction *math32.Vector3) {
+	event := &HitscanEvent{
+		Shooter: p.ID,
+		Weapon:  weapon.Name,
+		From:    p.Position.Clone(),
+		To:      p.Position.Clone().Add(direction.Clone().Normalize().MultiplyScalar(weapon.Range)),
+	}
+
+	hit := p.world.Raycast(p.Position, direction, weapon.Range, func(model Model) bool {
+		return model != p
+	})
+	if hit != nil {
+		event.To = hit.Point
+		switch target := hit.Model.(type) {
+		case *Player:
+			event.Target = target.ID
+			if target.takeDamage(weapon.Damage, p, weapon.Name) {
+				p.world.recordHit(p)
+			}
+		case *Missile:
+			event.Target = target.ID
+			target.Hit(weapon.Damage)
+		case *Enemy:
+			event.Target = target.ID
+			if target.takeDamage(weapon.Damage, p, weapon.Name) {
+				p.world.recordHit(p)
+			}
+		}
+	}
+
+	p.world.events.Publish(event)
+}
+
+func (p *Player) Reload() {
+	weapon := p.GetWeapon()
+	if weapon == nil {
+		return
+	}
+	state := p.GetWeaponState()
+	if state.startReload(weapon) {
+		p.onWeaponState(state)
+	}
+}
+
+func (p *Player) updateWeapons(deltaTime time.Duration) {
+	for name, state := range p.arsenal {
+		weapon := p.world.GetWeapon(name)
+		if weapon == nil {
+			continue
+		}
+		if state.update(weapon, deltaTime) {
+			p.onWeaponState(state)
+		}
+	}
+}
+
+func (p *Player) onWeaponState(state *WeaponState) {
+	p.world.events.Publish(&WeaponStateEvent{Player: p, State: state})
 }
 
 func (p *Player) Update(deltaTime time.Duration) {
+	p.updateTimers(deltaTime)
+	if p.Dead {
+		return
+	}
+
 	p.updateMoves()
+	p.updateWeapons(deltaTime)
 
 	p.Position.Add(p.Velocity)
+	p.Position.Add(p.knockback)
 
 	//direction := p.Direction.Clone()
 	leftAxis := p.GetLeftAxis().Clone()
@@ -204,7 +396,9 @@ func (p *Player) Update(deltaTime time.Duration) {
 		p.Direction.Normalize()
 	}
 
-	p.Velocity.MultiplyScalar(0.8)
+	inertia := p.world.modifyInertia(p, 0.8)
+	p.Velocity.MultiplyScalar(inertia)
+	p.knockback.MultiplyScalar(inertia)
 	p.VerticalAngle *= 0.8
 	p.HorizontalAngle *= 0.8
 }
@@ -225,14 +419,140 @@ func (p Player) GetHitBox() *math32.Sphere {
 	return math32.NewSphere(p.Position, 1)
 }
 
-func (p *Player) BulletHit(bullet *Bullet) {
-	p.hp -= bullet.hp
+// push knocks the player back. The impulse is kept apart from Velocity,
+// which the move keys overwrite, and fades out on its own.
+func (p *Player) push(impulse *math32.Vector3) {
+	p.knockback.Add(impulse)
+}
+
+func (p *Player) collideLevel(level *Level) {
+	hitBox := p.GetHitBox()
+	for _, normal := range level.PushOut(hitBox) {
+		if speed := p.Velocity.Dot(normal); speed < 0 {
+			p.Velocity.Sub(normal.Clone().MultiplyScalar(speed))
+		}
+		if speed := p.knockback.Dot(normal); speed < 0 {
+			p.knockback.Sub(normal.Clone().MultiplyScalar(speed))
+		}
+	}
+	p.Position.Copy(&hitBox.Center)
+}
+
+func (p *Player) BulletHit(bullet *Bullet) bool {
+	return p.takeDamage(bullet.Damage, bullet.Player, bullet.Weapon)
+}
+
+// takeDamage hurts the player and tells whether it took the hit, which it
+// does not when dead, invulnerable or protected from friendly fire.
+func (p *Player) takeDamage(amount int, attacker *Player, weapon string) bool {
+	if p.Dead || p.Invulnerable {
+		return false
+	}
+	if IsTeammate(p, attacker) {
+		switch p.world.FriendlyFire {
+		case FriendlyFireOn:
+		case FriendlyFireReflected:
+			attacker.takeDamage(amount, attacker, weapon)
+			return false
+		default:
+			return false
+		}
+	}
+	if attacker != nil {
+		amount = int(float32(amount) * attacker.damageMultiplier())
+	}
+	amount = p.world.modifyDamage(p, attacker, weapon, amount)
+	amount = p.absorb(amount)
+
+	event := &DamageEvent{
+		Victim: p.ID,
+		Amount: amount,
+		HP:     p.hp - amount,
+		Weapon: weapon,
+	}
+	if attacker != nil {
+		event.Attacker = attacker.ID
+	}
+	p.world.recordDamage(p, attacker, amount, weapon)
+	p.ApplyDamage(event)
+	p.world.onDamage(p, attacker, amount)
+
 	if p.hp <= 0 {
-		p.deleted = true
+		death := &DeathEvent{
+			Victim:     event.Victim,
+			VictimName: p.Name,
+			Attacker:   event.Attacker,
+			Weapon:     weapon,
+			RespawnIn:  conf.RespawnDelay,
+		}
+		if attacker != nil {
+			death.AttackerName = attacker.Name
+		}
+		p.world.recordKill(p, attacker)
+		if !p.world.Match.canRespawn(p) {
+			death.RespawnIn = 0
+			death.Eliminated = true
+		}
+		p.Kill(death)
+	}
+	return true
+}
+
+// ApplyDamage sets the hp reported by the damage event. Clients call it
+// with the events received from the server.
+func (p *Player) ApplyDamage(event *DamageEvent) {
+	p.hp = event.HP
+	p.world.events.Publish(event)
+}
+
+func (p *Player) SetHP(hp int) {
+	p.hp = hp
+	p.world.events.Publish(&HealthEvent{
+		Player: p.ID,
+		HP:     hp,
+	})
+}
+
+func (p *Player) Kill(event *DeathEvent) {
+	p.Dead = true
+	p.hp = 0
+	p.respawnTimer = event.RespawnIn
+	p.Velocity = math32.NewVec3()
+	p.knockback = math32.NewVec3()
+	p.world.events.Publish(event)
+}
+
+func (p *Player) Respawn(position math32.Vector3) {
+	p.Position = &position
+	p.Velocity = math32.NewVec3()
+	p.knockback = math32.NewVec3()
+	p.Dead = false
+	p.damagers = nil
+	p.Shield = 0
+	p.Effects = nil
+	p.Invulnerable = true
+	p.invulnerableTimer = conf.SpawnInvulnerability
+	p.SetHP(maxHP)
+	p.arsenal = nil
+	if state := p.GetWeaponState(); state != nil {
+		p.onWeaponState(state)
 	}
-	fmt.Println(p.hp)
-	if p.world.eventListener != nil {
-		p.world.eventListener.OnPlayerHit(p)
+	p.world.events.Publish(&PlayerRespawnedEvent{Player: p})
+}
+
+func (p *Player) updateTimers(deltaTime time.Duration) {
+	p.updateEffects(deltaTime)
+	if p.Invulnerable {
+		p.invulnerableTimer -= deltaTime
+		if p.invulnerableTimer <= 0 {
+			p.Invulnerable = false
+		}
+	}
+	if p.Dead && p.world.Match.canRespawn(p) {
+		p.respawnTimer -= deltaTime
+		if p.respawnTimer <= 0 {
+			p.Respawn(p.world.ChooseSpawnPoint(p))
+		}
 	}
 }
 
@@ -246,6 +566,16 @@ func (p *Player) Refresh(player Player) {
 	p.VerticalAngle = player.VerticalAngle
 }
 
+// RefreshState copies the state only the server is allowed to change. Clients
+// call it with the players received from the server.
+func (p *Player) RefreshState(player Player) {
+	p.Team = player.Team
+	p.SwitchWeapon(player.Weapon)
+	p.Invulnerable = player.Invulnerable
+	p.Shield = player.Shield
+	p.Effects = player.Effects
+}
+
 func (p *Player) RefreshMoves(moves *Moves) {
 	p.moves = moves
 }
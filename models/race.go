@@ -0,0 +1,242 @@
+package models
+
+import (
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+const ModeRace = "race"
+
+// maxGhostSamples bounds the ghost of a lap so it fits in one packet.
+const maxGhostSamples = 400
+
+// CheckpointEvent is sent when a player goes through the gate they had to
+// reach. Split is the time since the start of the lap and Delta its
+// difference with the same split of the player's best lap. LapTime is only
+// set when the gate completes a lap.
+type CheckpointEvent struct {
+	Player     string
+	PlayerName string
+	Gate       int
+	NextGate   int
+	Lap        int
+	Split      time.Duration
+	Delta      time.Duration
+	LapTime    time.Duration
+	BestLap    time.Duration
+	NewBest    bool
+}
+
+// Ghost is the path of the best lap so far, sampled every Interval. Points
+// holds the x, y, z coordinates of the samples one after the other.
+type Ghost struct {
+	Player   string
+	Name     string
+	LapTime  time.Duration
+	Interval time.Duration
+	Points   []float32
+}
+
+// GetPosition returns where the ghost is after the given time into its lap.
+func (g *Ghost) GetPosition(elapsed time.Duration) *math32.Vector3 {
+	count := len(g.Points) / 3
+	if count == 0 {
+		return math32.NewVec3()
+	}
+	index := float32(elapsed) / float32(g.Interval)
+	i := int(index)
+	if i >= count-1 {
+		return g.point(count - 1)
+	}
+	return g.point(i).Lerp(g.point(i+1), index-float32(i))
+}
+
+func (g *Ghost) point(i int) *math32.Vector3 {
+	return math32.NewVector3(g.Points[i*3], g.Points[i*3+1], g.Points[i*3+2])
+}
+
+type raceProgress struct {
+	position    *math32.Vector3
+	lap         int
+	nextGate    int
+	started     bool
+	lapStart    time.Duration
+	splits      []time.Duration
+	bestLap     time.Duration
+	bestSplits  []time.Duration
+	samples     []float32
+	sampleTimer time.Duration
+}
+
+// Race is a checkpoint race through the gates of the map. Players start a
+// lap by crossing the first gate and must go through every gate in order.
+// The score is the number of laps completed.
+type Race struct {
+	elapsed  time.Duration
+	progress map[string]*raceProgress
+	ghost    *Ghost
+	world    *World
+}
+
+func NewRace(world *World) *Race {
+	mode := &Race{
+		world: world,
+	}
+	mode.Reset()
+	return mode
+}
+
+func (m *Race) GetName() string {
+	return ModeRace
+}
+
+func (m *Race) IsTeamMode() bool {
+	return false
+}
+
+func (m *Race) GetScores() []*Score {
+	scores := make([]*Score, 0)
+	for _, player := range m.world.GetPlayers() {
+		score := 0
+		if progress, ok := m.progress[player.GetID()]; ok {
+			score = progress.lap
+		}
+		scores = append(scores, &Score{
+			ID:    player.GetID(),
+			Name:  player.Name,
+			Score: score,
+		})
+	}
+	return sortScores(scores)
+}
+
+// Reset clears the laps of every player. Best laps and the ghost are kept
+// from one match to the next.
+func (m *Race) Reset() {
+	m.elapsed = 0
+	previous := m.progress
+	m.progress = make(map[string]*raceProgress)
+	for id, progress := range previous {
+		m.progress[id] = &raceProgress{
+			bestLap:    progress.bestLap,
+			bestSplits: progress.bestSplits,
+		}
+	}
+}
+
+func (m *Race) Update(deltaTime time.Duration) {
+	m.elapsed += deltaTime
+	gates := m.world.Gates
+	if len(gates) == 0 {
+		return
+	}
+
+	for _, player := range m.world.GetPlayers() {
+		progress, ok := m.progress[player.GetID()]
+		if !ok {
+			progress = &raceProgress{}
+			m.progress[player.GetID()] = progress
+		}
+		from := progress.position
+		progress.position = player.Position.Clone()
+		if player.Dead || from == nil {
+			continue
+		}
+
+		if progress.started {
+			progress.sampleTimer -= deltaTime
+			if progress.sampleTimer <= 0 && len(progress.samples) < maxGhostSamples*3 {
+				progress.sampleTimer += conf.GhostInterval
+				progress.samples = append(progress.samples, player.Position.X, player.Position.Y, player.Position.Z)
+			}
+		}
+
+		if gates[progress.nextGate].Crossed(from, player.Position) {
+			m.checkpoint(player, progress)
+		}
+	}
+}
+
+func (m *Race) checkpoint(player *Player, progress *raceProgress) {
+	gates := m.world.Gates
+	gate := progress.nextGate
+	progress.nextGate = (gate + 1) % len(gates)
+	event := &CheckpointEvent{
+		Player:     player.GetID(),
+		PlayerName: player.Name,
+		Gate:       gate,
+		NextGate:   progress.nextGate,
+	}
+
+	if !progress.started {
+		progress.started = true
+		m.startLap(progress)
+	} else {
+		split := m.elapsed - progress.lapStart
+		event.Split = split
+		if gate == 0 {
+			// The start line closes the lap, its split is the lap time.
+			event.LapTime = split
+			progress.lap++
+			if progress.bestLap == 0 || split < progress.bestLap {
+				progress.bestLap = split
+				progress.bestSplits = append(progress.splits, split)
+				event.NewBest = true
+			}
+			m.recordGhost(player, progress, split)
+			m.startLap(progress)
+		} else {
+			if len(progress.bestSplits) > len(progress.splits) {
+				event.Delta = split - progress.bestSplits[len(progress.splits)]
+			}
+			progress.splits = append(progress.splits, split)
+		}
+	}
+	event.Lap = progress.lap
+	event.BestLap = progress.bestLap
+
+	m.world.events.Publish(event)
+}
+
+func (m *Race) startLap(progress *raceProgress) {
+	progress.lapStart = m.elapsed
+	progress.splits = nil
+	progress.samples = nil
+	progress.sampleTimer = 0
+}
+
+// recordGhost keeps the lap as the ghost when it is the best of the server.
+func (m *Race) recordGhost(player *Player, progress *raceProgress, lapTime time.Duration) {
+	if m.ghost != nil && m.ghost.LapTime <= lapTime {
+		return
+	}
+	m.ghost = &Ghost{
+		Player:   player.GetID(),
+		Name:     player.Name,
+		LapTime:  lapTime,
+		Interval: conf.GhostInterval,
+		Points:   progress.samples,
+	}
+	m.world.events.Publish(&GhostEvent{Ghost: m.ghost})
+}
+
+// GetGhost returns the ghost of the best lap, nil before the first lap.
+func (m *Race) GetGhost() *Ghost {
+	return m.ghost
+}
+
+func (m *Race) OnJoin(player *Player) {
+}
+
+func (m *Race) OnKill(victim, killer *Player) {
+}
+
+func (m *Race) CanRespawn(player *Player) bool {
+	return true
+}
+
+func (m *Race) IsOver() bool {
+	return false
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"github.com/g3n/engine/math32"
+)
+
+// RaycastHit describes the first thing a ray touched. Model is nil when the
+// ray was stopped by an obstacle.
+type RaycastHit struct {
+	Model    Model
+	Obstacle *Obstacle
+	Point    *math32.Vector3
+	Distance float32
+}
+
+// RaycastFilter tells whether a model can be hit by the ray.
+type RaycastFilter func(model Model) bool
+
+// Raycast returns the first living player, missile, enemy or obstacle hit
+// by the ray within maxDistance, or nil when the ray hits nothing. A nil filter
+// accepts every model.
+func (w *World) Raycast(origin, direction *math32.Vector3, maxDistance float32, filter RaycastFilter) *RaycastHit {
+	direction = direction.Clone().Normalize()
+
+	var hit *RaycastHit
+	for _, player := range w.GetPlayers() {
+		if player.Dead || (filter != nil && !filter(player)) {
+			continue
+		}
+		hitBox := player.GetHitBox()
+		distance, ok := intersectSphere(origin, direction, &hitBox.Center, hitBox.Radius)
+		if !ok || distance > maxDistance {
+			continue
+		}
+		if hit == nil || distance < hit.Distance {
+			hit = &RaycastHit{
+				Model:    player,
+				Distance: distance,
+			}
+		}
+	}
+
+	for _, missile := range w.GetMissiles() {
+		if filter != nil && !filter(missile) {
+			continue
+		}
+		hitBox := missile.GetHitBox()
+		distance, ok := intersectSphere(origin, direction, &hitBox.Center, hitBox.Radius)
+		if !ok || distance > maxDistance {
+			continue
+		}
+		if hit == nil || distance < hit.Distance {
+			hit = &RaycastHit{
+				Model:    missile,
+				Distance: distance,
+			}
+		}
+	}
+
+	for _, enemy := range w.GetEnemies() {
+		if enemy.deleted || (filter != nil && !filter(enemy)) {
+			continue
+		}
+		hitBox := enemy.GetHitBox()
+		distance, ok := intersectSphere(origin, direction, &hitBox.Center, hitBox.Radius)
+		if !ok || distance > maxDistance {
+			continue
+		}
+		if hit == nil || distance < hit.Distance {
+			hit = &RaycastHit{
+				Model:    enemy,
+				Distance: distance,
+			}
+		}
+	}
+
+	if obstacle, distance, ok := w.Level.Raycast(origin, direction, maxDistance); ok {
+		if hit == nil || distance < hit.Distance {
+			hit = &RaycastHit{
+				Obstacle: obstacle,
+				Distance: distance,
+			}
+		}
+	}
+
+	if hit != nil {
+		hit.Point = origin.Clone().Add(direction.Clone().MultiplyScalar(hit.Distance))
+	}
+	return hit
+}
+
+// LineOfSight tells whether no obstacle stands between the two points.
+func (w *World) LineOfSight(from, to *math32.Vector3) bool {
+	direction := to.Clone().Sub(from)
+	distance := direction.Length()
+	if distance == 0 {
+		return true
+	}
+	_, _, blocked := w.Level.Raycast(from, direction.Normalize(), distance)
+	return !blocked
+}
+
+// Raycast returns the nearest obstacle hit by the ray. The direction must be
+// normalized.
+func (l *Level) Raycast(origin, direction *math32.Vector3, maxDistance float32) (*Obstacle, float32, bool) {
+	if l == nil {
+		return nil, 0, false
+	}
+
+	var nearest *Obstacle
+	nearestDistance := maxDistance
+	for _, obstacle := range l.Obstacles {
+		distance, ok := obstacle.Raycast(origin, direction)
+		if ok && distance <= nearestDistance {
+			nearest = obstacle
+			nearestDistance = distance
+		}
+	}
+	return nearest, nearestDistance, nearest != nil
+}
+
+// Raycast returns the distance along the ray to the obstacle surface. The
+// direction must be normalized.
+func (o *Obstacle) Raycast(origin, direction *math32.Vector3) (float32, bool) {
+	switch o.Kind {
+	case ObstacleBox:
+		point := math32.NewRay(origin, direction).IntersectBox(o.GetBox(), nil)
+		if point == nil {
+			return 0, false
+		}
+		return point.DistanceTo(origin), true
+	case ObstacleSphere:
+		return intersectSphere(origin, direction, o.Position, o.Radius)
+	case ObstacleMesh:
+		return o.raycastMesh(origin, direction)
+	}
+	return 0, false
+}
+
+// raycastMesh clips the ray against every face plane of the convex mesh.
+func (o *Obstacle) raycastMesh(origin, direction *math32.Vector3) (float32, bool) {
+	near := float32(0)
+	far := math32.Infinity
+	for _, p := range o.getPlanes() {
+		denominator := p.normal.Dot(direction)
+		distance := p.distanceToPoint(origin)
+		if denominator == 0 {
+			if distance > 0 {
+				return 0, false
+			}
+			continue
+		}
+		t := -distance / denominator
+		if denominator < 0 {
+			near = math32.Max(near, t)
+		} else {
+			far = math32.Min(far, t)
+		}
+		if near > far {
+			return 0, false
+		}
+	}
+	return near, len(o.Faces) > 0
+}
+
+func intersectSphere(origin, direction, center *math32.Vector3, radius float32) (float32, bool) {
+	toCenter := center.Clone().Sub(origin)
+	projection := toCenter.Dot(direction)
+	distance2 := toCenter.Dot(toCenter) - projection*projection
+	if distance2 > radius*radius {
+		return 0, false
+	}
+	half := math32.Sqrt(radius*radius - distance2)
+	if projection+half < 0 {
+		return 0, false
+	}
+	if projection-half < 0 {
+		return 0, true
+	}
+	return projection - half, true
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/g3n/engine/math32"
+)
+
+func TestRaycast(t *testing.T) {
+	wall := NewBoxObstacle(math32.Vector3{Z: -10}, math32.Vector3{X: 10, Y: 10, Z: 2}, "")
+	tests := []struct {
+		name     string
+		player   math32.Vector3
+		model    bool
+		distance float32
+	}{
+		{name: "player before the wall", player: math32.Vector3{Z: -5}, model: true, distance: 4},
+		{name: "player behind the wall", player: math32.Vector3{Z: -15}, model: false, distance: 9},
+		{name: "player off the ray", player: math32.Vector3{X: 5, Z: -5}, model: false, distance: 9},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			world := &World{Level: &Level{Obstacles: []*Obstacle{wall}}}
+			world.AddPlayer(NewPlayer("player", world, "player", test.player))
+
+			hit := world.Raycast(math32.NewVec3(), math32.NewVector3(0, 0, -1), 50, nil)
+			if hit == nil {
+				t.Fatal("ray hit nothing")
+			}
+			if (hit.Model != nil) != test.model {
+				t.Errorf("hit model %v, obstacle %v", hit.Model, hit.Obstacle)
+			}
+			if math32.Abs(hit.Distance-test.distance) > 0.01 {
+				t.Errorf("distance = %v, want %v", hit.Distance, test.distance)
+			}
+		})
+	}
+}
+
+func TestLineOfSight(t *testing.T) {
+	world := &World{Level: &Level{Obstacles: []*Obstacle{
+		NewSphereObstacle(math32.Vector3{}, 3, ""),
+	}}}
+	if world.LineOfSight(math32.NewVector3(-10, 0, 0), math32.NewVector3(10, 0, 0)) {
+		t.Error("line of sight through the sphere")
+	}
+	if !world.LineOfSight(math32.NewVector3(-10, 5, 0), math32.NewVector3(10, 5, 0)) {
+		t.Error("no line of sight above the sphere")
+	}
+}
+
+func TestCollidePlayers(t *testing.T) {
+	world := &World{}
+	a := NewPlayer("a", world, "a", math32.Vector3{X: -0.5})
+	b := NewPlayer("b", world, "b", math32.Vector3{X: 0.5})
+	a.Velocity = math32.NewVector3(1, 0, 0)
+
+	collision := collidePlayers(a, b)
+	if collision == nil {
+		t.Fatal("overlapping ships did not collide")
+	}
+	if distance := a.Position.DistanceTo(b.Position); distance < 2-0.01 {
+		t.Errorf("ships still overlap, distance %v", distance)
+	}
+	if a.Velocity.X >= b.Velocity.X {
+		t.Errorf("ships still approach, velocities %v and %v", a.Velocity, b.Velocity)
+	}
+	if collision.Impulse <= 0 {
+		t.Errorf("impulse = %v, want > 0", collision.Impulse)
+	}
+}
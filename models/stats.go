@@ -0,0 +1,102 @@
+package models
+
+import (
+	"sort"
+)
+
+// Stats are the per-match statistics of a player.
+type Stats struct {
+	Player      string
+	Name        string
+	Kills       int
+	Deaths      int
+	Assists     int
+	DamageDealt int
+	ShotsFired  int
+	ShotsHit    int
+}
+
+func (s Stats) GetAccuracy() float32 {
+	if s.ShotsFired == 0 {
+		return 0
+	}
+	return float32(s.ShotsHit) / float32(s.ShotsFired)
+}
+
+func (w *World) GetStats(player *Player) *Stats {
+	if w.stats == nil {
+		w.stats = make(map[string]*Stats)
+	}
+	stats, ok := w.stats[player.GetID()]
+	if !ok {
+		stats = &Stats{
+			Player: player.GetID(),
+		}
+		w.stats[player.GetID()] = stats
+	}
+	stats.Name = player.Name
+	return stats
+}
+
+// GetScoreboard returns the stats of the players still in the world, best
+// first.
+func (w *World) GetScoreboard() []*Stats {
+	scoreboard := make([]*Stats, 0)
+	for _, player := range w.GetPlayers() {
+		scoreboard = append(scoreboard, w.GetStats(player))
+	}
+	sort.Slice(scoreboard, func(i, j int) bool {
+		if scoreboard[i].Kills != scoreboard[j].Kills {
+			return scoreboard[i].Kills > scoreboard[j].Kills
+		}
+		return scoreboard[i].Deaths < scoreboard[j].Deaths
+	})
+	return scoreboard
+}
+
+func (w *World) ResetStats() {
+	w.stats = nil
+}
+
+func (w *World) recordShot(shooter *Player) {
+	w.GetStats(shooter).ShotsFired++
+}
+
+// recordHit counts a shot that hurt someone. Each bullet, missile or ray
+// counts once, however many targets its impact and explosion reached, so
+// accuracy never goes above 100%.
+func (w *World) recordHit(shooter *Player) {
+	if shooter != nil {
+		w.GetStats(shooter).ShotsHit++
+	}
+}
+
+func (w *World) recordDamage(victim, attacker *Player, amount int, weapon string) {
+	if attacker == nil || attacker == victim {
+		return
+	}
+	w.GetStats(attacker).DamageDealt += amount
+	if victim.damagers == nil {
+		victim.damagers = make(map[string]*Player)
+	}
+	victim.damagers[attacker.GetID()] = attacker
+}
+
+// recordKill credits the killer and every other player who damaged the
+// victim since its last spawn.
+func (w *World) recordKill(victim, killer *Player) {
+	w.GetStats(victim).Deaths++
+	if killer != nil && killer != victim && !IsTeammate(victim, killer) {
+		w.GetStats(killer).Kills++
+	}
+	for _, damager := range victim.damagers {
+		if damager != killer && damager != victim {
+			w.GetStats(damager).Assists++
+		}
+	}
+	victim.damagers = nil
+
+	if w.Match != nil {
+		w.Match.GetMode().OnKill(victim, killer)
+	}
+}
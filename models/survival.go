@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lambher/video-game/conf"
+)
+
+const ModeSurvival = "survival"
+
+// WaveState is the survival progress shown on the HUD. NextIn is the time
+// left before the next wave while Break is set.
+type WaveState struct {
+	Wave      int
+	Total     int
+	Remaining int
+	Break     bool
+	NextIn    time.Duration
+}
+
+// Survival is a cooperative mode against waves of enemies. Each wave is
+// bigger and tougher than the previous one. Players killed during a wave
+// come back during the break that follows it, and the match is lost once
+// they are all down at the same time. Scores are enemy kills.
+type Survival struct {
+	wave       int
+	total      int
+	inWave     bool
+	breakTimer time.Duration
+	world      *World
+}
+
+func NewSurvival(world *World) *Survival {
+	mode := &Survival{
+		world: world,
+	}
+	mode.Reset()
+	return mode
+}
+
+func (m *Survival) GetName() string {
+	return ModeSurvival
+}
+
+func (m *Survival) IsTeamMode() bool {
+	return false
+}
+
+func (m *Survival) GetScores() []*Score {
+	scores := make([]*Score, 0)
+	for _, player := range m.world.GetPlayers() {
+		scores = append(scores, &Score{
+			ID:    player.GetID(),
+			Name:  player.Name,
+			Score: m.world.GetStats(player).Kills,
+		})
+	}
+	return sortScores(scores)
+}
+
+// Reset clears the enemies left from the previous match and goes back to the
+// break before the first wave.
+func (m *Survival) Reset() {
+	for _, enemy := range m.world.GetEnemies() {
+		m.world.RemoveModel(enemy.GetID())
+	}
+	m.wave = 0
+	m.total = 0
+	m.inWave = false
+	m.breakTimer = conf.WaveBreak
+}
+
+func (m *Survival) Update(deltaTime time.Duration) {
+	if m.inWave {
+		if len(m.world.GetEnemies()) == 0 {
+			m.inWave = false
+			m.breakTimer = conf.WaveBreak
+		}
+		return
+	}
+
+	m.breakTimer -= deltaTime
+	if m.breakTimer > 0 {
+		return
+	}
+	m.wave++
+	m.inWave = true
+	m.total = conf.WaveEnemies + (m.wave-1)*conf.WaveEnemiesStep + len(m.world.GetPlayers()) - 1
+	toughness := 1 + float32(m.wave-1)*conf.WaveToughness
+	for i := 0; i < m.total; i++ {
+		m.world.AddEnemy(NewEnemy(m.world, toughness))
+	}
+}
+
+// GetWave returns the progress of the current wave.
+func (m *Survival) GetWave() *WaveState {
+	state := &WaveState{
+		Wave:  m.wave,
+		Total: m.total,
+		Break: !m.inWave,
+	}
+	if m.inWave {
+		state.Remaining = len(m.world.GetEnemies())
+	} else {
+		state.NextIn = m.breakTimer
+	}
+	return state
+}
+
+func (m *Survival) OnJoin(player *Player) {
+}
+
+func (m *Survival) OnKill(victim, killer *Player) {
+}
+
+func (m *Survival) CanRespawn(player *Player) bool {
+	return !m.inWave || !m.world.Match.IsRunning()
+}
+
+// IsOver ends the match when every player is down during a wave, or once
+// conf.SurvivalWaves waves are cleared when it is set.
+func (m *Survival) IsOver() bool {
+	if conf.SurvivalWaves > 0 && m.wave >= conf.SurvivalWaves && !m.inWave {
+		return true
+	}
+	if !m.inWave {
+		return false
+	}
+	for _, player := range m.world.GetPlayers() {
+		if !player.Dead {
+			return false
+		}
+	}
+	return true
+}
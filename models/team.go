@@ -0,0 +1,119 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	TeamRed  = "red"
+	TeamBlue = "blue"
+)
+
+var Teams = []string{TeamRed, TeamBlue}
+
+func isTeam(team string) bool {
+	for _, t := range Teams {
+		if t == team {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	FriendlyFireOff       = "off"
+	FriendlyFireOn        = "on"
+	FriendlyFireReflected = "reflected"
+)
+
+const ModeTeamDeathmatch = "tdm"
+
+// TeamDeathmatch scores one point per enemy kill for the killer's team and
+// takes one point away for a team kill.
+type TeamDeathmatch struct {
+	scores map[string]int
+	world  *World
+}
+
+func NewTeamDeathmatch(world *World) *TeamDeathmatch {
+	return &TeamDeathmatch{
+		scores: make(map[string]int),
+		world:  world,
+	}
+}
+
+func (m *TeamDeathmatch) GetName() string {
+	return ModeTeamDeathmatch
+}
+
+func (m *TeamDeathmatch) IsTeamMode() bool {
+	return true
+}
+
+func (m *TeamDeathmatch) GetScores() []*Score {
+	return teamScores(m.scores)
+}
+
+func (m *TeamDeathmatch) Reset() {
+	m.scores = make(map[string]int)
+}
+
+func (m *TeamDeathmatch) Update(deltaTime time.Duration) {
+}
+
+func (m *TeamDeathmatch) OnJoin(player *Player) {
+	player.Team = m.world.smallestTeam(m.scores)
+}
+
+func (m *TeamDeathmatch) OnKill(victim, killer *Player) {
+	if killer == nil || killer == victim || killer.Team == "" {
+		return
+	}
+	if killer.Team == victim.Team {
+		m.scores[killer.Team]--
+		return
+	}
+	m.scores[killer.Team]++
+}
+
+func (m *TeamDeathmatch) CanRespawn(player *Player) bool {
+	return true
+}
+
+func (m *TeamDeathmatch) IsOver() bool {
+	return false
+}
+
+func teamScores(scores map[string]int) []*Score {
+	result := make([]*Score, 0, len(Teams))
+	for _, team := range Teams {
+		result = append(result, &Score{
+			ID:    team,
+			Name:  strings.ToUpper(team),
+			Score: scores[team],
+		})
+	}
+	return sortScores(result)
+}
+
+// smallestTeam returns the team with the fewest players, the one with the
+// lowest score on a tie.
+func (w *World) smallestTeam(scores map[string]int) string {
+	counts := make(map[string]int)
+	for _, player := range w.GetPlayers() {
+		counts[player.Team]++
+	}
+	best := Teams[0]
+	for _, team := range Teams[1:] {
+		if counts[team] < counts[best] || (counts[team] == counts[best] && scores[team] < scores[best]) {
+			best = team
+		}
+	}
+	return best
+}
+
+// IsTeammate tells whether two different players are on the same team.
+func IsTeammate(a, b *Player) bool {
+	return a != nil && b != nil && a != b && a.Team != "" && a.Team == b.Team
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+const (
+	ProjectileBullet  = "bullet"
+	ProjectileMissile = "missile"
+)
+
+// Weapon is a weapon definition loaded from conf.WeaponsFile. Speeds are in
+// units per tick, FireRate in shots per second, Spread in radians and times
+// in seconds. Hitscan weapons hit instantly up to Range instead of spawning
+// projectiles. Missiles lock onto the enemy nearest the crosshair within
+// LockCone radians and LockRange, then turn by at most TurnRate per tick.
+// Explosive projectiles detonate on impact or timeout and damage everything
+// within ExplosionRadius.
+type Weapon struct {
+	Name            string
+	Damage          int
+	Hitscan         bool
+	Range           float32
+	Projectile      string
+	ProjectileSpeed float32
+	TurnRate        float32
+	LockCone        float32
+	LockRange       float32
+	ExplosionRadius float32
+	ExplosionDamage int
+	Knockback       float32
+	FireRate        float32
+	Spread          float32
+	Lifetime        float32
+	ProjectileCount int
+	Magazine        int
+	ReserveAmmo     int
+	ReloadTime      float32
+}
+
+func LoadWeapons(path string) ([]*Weapon, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	weapons := make([]*Weapon, 0)
+	err = json.Unmarshal(data, &weapons)
+	if err != nil {
+		return nil, err
+	}
+
+	return weapons, nil
+}
+
+func (w Weapon) GetLifetime() time.Duration {
+	return seconds(w.Lifetime)
+}
+
+// getReach returns how far the weapon hits. Projectiles move
+// ProjectileSpeed every world update, conf.TickTimeWorld, until their
+// lifetime runs out.
+func (w Weapon) getReach() float32 {
+	if w.Hitscan {
+		return w.Range
+	}
+	if w.GetLifetime() <= 0 {
+		return math32.Infinity
+	}
+	return w.ProjectileSpeed * float32(w.GetLifetime()) / float32(conf.TickTimeWorld)
+}
+
+func (w Weapon) GetCooldown() time.Duration {
+	if w.FireRate <= 0 {
+		return 0
+	}
+	return seconds(1 / w.FireRate)
+}
+
+func (w Weapon) GetReloadTime() time.Duration {
+	return seconds(w.ReloadTime)
+}
+
+// spread returns a copy of direction deviated randomly inside the weapon
+// cone.
+func (w Weapon) spread(direction, up *math32.Vector3) *math32.Vector3 {
+	result := direction.Clone()
+	if w.Spread <= 0 {
+		return result
+	}
+	axis := up.Clone().ApplyAxisAngle(direction, rand.Float32()*2*math32.Pi)
+	return result.ApplyAxisAngle(axis, rand.Float32()*w.Spread)
+}
+
+func seconds(value float32) time.Duration {
+	return time.Duration(value * float32(time.Second))
+}
+
+var (
+	ErrDead      = errors.New("player is dead")
+	ErrNoWeapon  = errors.New("unknown weapon")
+	ErrCooldown  = errors.New("weapon is cooling down")
+	ErrReloading = errors.New("weapon is reloading")
+	ErrNoAmmo    = errors.New("out of ammo")
+	ErrNoCombat  = errors.New("combat is not allowed in this match phase")
+	ErrNoWeapons = errors.New("weapons are disabled on this map")
+)
+
+// WeaponState is the ammunition a player has left for one weapon.
+type WeaponState struct {
+	Weapon    string
+	Ammo      int
+	Reserve   int
+	Reloading bool
+
+	cooldown    time.Duration
+	reloadTimer time.Duration
+}
+
+func newWeaponState(weapon *Weapon) *WeaponState {
+	return &WeaponState{
+		Weapon:  weapon.Name,
+		Ammo:    weapon.Magazine,
+		Reserve: weapon.ReserveAmmo,
+	}
+}
+
+func (s *WeaponState) canFire() error {
+	if s.Reloading {
+		return ErrReloading
+	}
+	if s.cooldown > 0 {
+		return ErrCooldown
+	}
+	if s.Ammo <= 0 {
+		return ErrNoAmmo
+	}
+	return nil
+}
+
+func (s *WeaponState) startReload(weapon *Weapon) bool {
+	if s.Reloading || s.Ammo >= weapon.Magazine || s.Reserve <= 0 {
+		return false
+	}
+	s.Reloading = true
+	s.reloadTimer = weapon.GetReloadTime()
+	return true
+}
+
+// update advances the cooldown and reload timers. It returns true when a
+// reload just finished.
+func (s *WeaponState) update(weapon *Weapon, deltaTime time.Duration) bool {
+	if s.cooldown > 0 {
+		s.cooldown -= deltaTime
+	}
+	if !s.Reloading {
+		return false
+	}
+	s.reloadTimer -= deltaTime
+	if s.reloadTimer > 0 {
+		return false
+	}
+	amount := weapon.Magazine - s.Ammo
+	if amount > s.Reserve {
+		amount = s.Reserve
+	}
+	s.Ammo += amount
+	s.Reserve -= amount
+	s.Reloading = false
+	return true
+}
@@ -2,69 +2,134 @@ package models
 
 import (
 	"encoding/json"
-	"sync"
 	"time"
+
+	"github.com/g3n/engine/math32"
 )
 
-type World struct {
-	Player        *Player
-	players       map[string]*Player
-	models        map[string]Model
-	eventListener EventListener
+const (
+	WeaponRam  = "ram"
+	WeaponZone = "zone"
+)
+
+type DamageEvent struct {
+	Attacker string
+	Victim   string
+	Amount   int
+	HP       int
+	Weapon   string
+}
+
+type HitscanEvent struct {
+	Shooter string
+	Target  string
+	Weapon  string
+	From    *math32.Vector3
+	To      *math32.Vector3
+}
+
+type HealthEvent struct {
+	Player string
+	HP     int
+}
+
+type DeathEvent struct {
+	Attacker     string
+	AttackerName string
+	Victim       string
+	VictimName   string
+	Weapon       string
+	RespawnIn    time.Duration
+	Eliminated   bool
+}
 
-	playersLock sync.RWMutex
-	playerLock  sync.RWMutex
+// World is not safe for concurrent use. A single goroutine owns it: gameLoop
+// on the server, which other goroutines hand work to through its command
+// queue, and the Listen goroutine of a client, see client.Client.Do.
+type World struct {
+	Player       *Player
+	Level        *Level
+	SpawnPoints  []*math32.Vector3
+	Weapons      []*Weapon
+	Match        *Match
+	SafeZone     *SafeZone
+	Gates        []*Gate
+	Nav          *NavGraph
+	NoWeapons    bool
+	Mutators     []Mutator
+	FriendlyFire string
+	players      map[string]*Player
+	models       map[string]Model
+	events       EventBus
+	stats        map[string]*Stats
 }
 
 type EventListener interface {
 	OnAddPlayer(player *Player)
-	OnPlayerHit(player *Player)
+	OnPlayerDamage(event *DamageEvent)
+	OnPlayerHealth(event *HealthEvent)
+	OnPlayersCollide(collision *Collision)
+	OnPlayerDeath(event *DeathEvent)
+	OnPlayerRespawn(player *Player)
+	OnWeaponState(player *Player, state *WeaponState)
 	OnAddBullet(bullet *Bullet)
+	OnAddMissile(missile *Missile)
+	OnHitscan(event *HitscanEvent)
+	OnExplosion(event *ExplosionEvent)
+	OnAddPickup(pickup *Pickup)
+	OnMatchState(state *MatchState)
+	OnPickup(event *PickupEvent)
+	OnAddFlag(flag *Flag)
+	OnFlag(event *FlagEvent)
+	OnAddZone(zone *Zone)
+	OnAddEnemy(enemy *Enemy)
+	OnCheckpoint(event *CheckpointEvent)
+	OnGhost(ghost *Ghost)
 	OnRemoveModel(model Model)
 }
 
 func (w *World) GetPlayerData() ([]byte, error) {
-	w.playerLock.Lock()
-	playerData, err := json.Marshal(w.Player)
-	w.playerLock.Unlock()
-
-	return playerData, err
+	return json.Marshal(w.Player)
 }
 
 func (w *World) GetPlayerMoveData() ([]byte, error) {
-	w.playerLock.Lock()
-	moveData, err := json.Marshal(w.Player.moves)
-	w.playerLock.Unlock()
-
-	return moveData, err
+	return json.Marshal(w.Player.moves)
 }
 
 func (w *World) GetPlayer(id string) *Player {
-	var player *Player
-
-	w.playersLock.Lock()
-	if p, ok := w.players[id]; ok {
-		player = p
-	}
-	w.playersLock.Unlock()
-
-	return player
+	return w.players[id]
 }
 
 func (w *World) GetPlayers() []*Player {
 	players := make([]*Player, 0)
 
-	w.playersLock.Lock()
 	for _, player := range w.players {
 		players = append(players, player)
 	}
-	w.playersLock.Unlock()
 
 	return players
 }
 
-func (w *World) SubscribeEventListener(e EventListener) {
-	w.eventListener = e
+func (w *World) GetWeapon(name string) *Weapon {
+	for _, weapon := range w.Weapons {
+		if weapon.Name == name {
+			return weapon
+		}
+	}
+	return nil
+}
+
+func (w *World) GetDefaultWeapon() string {
+	if len(w.Weapons) == 0 {
+		return ""
+	}
+	return w.Weapons[0].Name
+}
+
+// Subscribe calls the handler with every event of the world until the
+// returned function is called.
+func (w *World) Subscribe(handler EventHandler) func() {
+	return w.events.Subscribe(handler)
 }
 
 func (w *World) AddPlayer(player *Player) {
@@ -74,36 +139,117 @@ func (w *World) AddPlayer(player *Player) {
 	if w.Player == nil {
 		w.Player = player
 	}
-	w.players[player.GetID()] = player
-	if w.eventListener != nil {
-		w.eventListener.OnAddPlayer(player)
+	if w.Match != nil {
+		w.Match.GetMode().OnJoin(player)
 	}
+	w.players[player.GetID()] = player
+	w.events.Publish(&PlayerJoinedEvent{Player: player})
 }
 
 func (w *World) RemovePlayer(player *Player) {
 	delete(w.players, player.GetID())
-	if w.eventListener != nil {
-		w.eventListener.OnRemoveModel(player)
-	}
+	w.events.Publish(&RemovedEvent{Model: player})
 }
 
 func (w *World) AddBullet(bullet *Bullet) {
 	if w.models == nil {
 		w.models = make(map[string]Model)
 	}
+	if bullet.world == nil {
+		bullet.world = w
+	}
+	if bullet.Player == nil {
+		bullet.Player = w.GetPlayer(bullet.Owner)
+	}
 	w.models[bullet.ID] = bullet
-	if w.eventListener != nil {
-		w.eventListener.OnAddBullet(bullet)
+	w.events.Publish(&ProjectileSpawnedEvent{Projectile: bullet})
+}
+
+func (w *World) AddMissile(missile *Missile) {
+	if w.models == nil {
+		w.models = make(map[string]Model)
 	}
+	if missile.world == nil {
+		missile.world = w
+	}
+	if missile.Player == nil {
+		missile.Player = w.GetPlayer(missile.Owner)
+	}
+	w.models[missile.ID] = missile
+	w.events.Publish(&ProjectileSpawnedEvent{Projectile: missile})
 }
 
-func (w *World) removeModel(model Model) {
-	if w.eventListener != nil {
-		w.eventListener.OnRemoveModel(model)
+func (w *World) AddPickup(pickup *Pickup) {
+	if w.models == nil {
+		w.models = make(map[string]Model)
+	}
+	if pickup.world == nil {
+		pickup.world = w
+	}
+	w.models[pickup.ID] = pickup
+	w.events.Publish(&PickupSpawnedEvent{Pickup: pickup})
+}
+
+func (w *World) GetPickups() []*Pickup {
+	pickups := make([]*Pickup, 0)
+	for _, model := range w.models {
+		if pickup, ok := model.(*Pickup); ok {
+			pickups = append(pickups, pickup)
+		}
 	}
+	return pickups
+}
+
+func (w *World) GetPickup(id string) *Pickup {
+	if pickup, ok := w.models[id].(*Pickup); ok {
+		return pickup
+	}
+	return nil
+}
+
+func (w *World) GetMissiles() []*Missile {
+	missiles := make([]*Missile, 0)
+	for _, model := range w.models {
+		if missile, ok := model.(*Missile); ok {
+			missiles = append(missiles, missile)
+		}
+	}
+	return missiles
+}
+
+func (w *World) GetMissile(id string) *Missile {
+	if missile, ok := w.models[id].(*Missile); ok {
+		return missile
+	}
+	return nil
+}
+
+// IsTargeted tells whether a missile is locked onto the player.
+func (w *World) IsTargeted(player *Player) bool {
+	for _, missile := range w.GetMissiles() {
+		if missile.Target == player.GetID() {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *World) RemoveModel(id string) {
+	if model, ok := w.models[id]; ok {
+		delete(w.models, id)
+		w.removeModel(model)
+	}
+}
+
+func (w *World) removeModel(model Model) {
+	w.events.Publish(&RemovedEvent{Model: model})
 }
 
 func (w *World) Update(deltaTime time.Duration) {
+	if w.Match != nil {
+		w.Match.Update(deltaTime)
+	}
+
 	players := make(map[string]*Player)
 	for _, player := range w.players {
 		player.Update(deltaTime)
@@ -115,6 +261,13 @@ func (w *World) Update(deltaTime time.Duration) {
 	}
 	w.players = players
 
+	w.collidePlayers()
+	for _, player := range w.players {
+		if !player.Dead {
+			player.collideLevel(w.Level)
+		}
+	}
+
 	models := make(map[string]Model)
 
 	for _, model := range w.models {
@@ -128,6 +281,57 @@ func (w *World) Update(deltaTime time.Duration) {
 	w.models = models
 }
 
+// ChooseSpawnPoint returns the spawn point farthest from the closest living
+// enemy of the player.
+func (w *World) ChooseSpawnPoint(player *Player) math32.Vector3 {
+	if len(w.SpawnPoints) == 0 {
+		return math32.Vector3{}
+	}
+
+	best := w.SpawnPoints[0]
+	bestDistance := float32(-1)
+	for _, spawnPoint := range w.SpawnPoints {
+		nearest := math32.Infinity
+		for _, enemy := range w.players {
+			if enemy == player || enemy.Dead || IsTeammate(enemy, player) {
+				continue
+			}
+			if distance := enemy.Position.DistanceTo(spawnPoint); distance < nearest {
+				nearest = distance
+			}
+		}
+		if nearest > bestDistance {
+			best = spawnPoint
+			bestDistance = nearest
+		}
+	}
+
+	return *best
+}
+
+func (w *World) collidePlayers() {
+	players := make([]*Player, 0, len(w.players))
+	for _, player := range w.players {
+		if !player.Dead {
+			players = append(players, player)
+		}
+	}
+
+	for i := 0; i < len(players); i++ {
+		for j := i + 1; j < len(players); j++ {
+			collision := collidePlayers(players[i], players[j])
+			if collision == nil {
+				continue
+			}
+			w.events.Publish(collision)
+			if collision.Damage > 0 {
+				players[i].takeDamage(collision.Damage, players[j], WeaponRam)
+				players[j].takeDamage(collision.Damage, players[i], WeaponRam)
+			}
+		}
+	}
+}
+
 func (w *World) UpdatePositions(deltaTime time.Duration) {
 	for _, player := range w.players {
 		player.UpdatePosition(deltaTime)
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+	"github.com/rs/xid"
+)
+
+// Zone is a spherical control zone for king of the hill. Holder is the team,
+// or the player outside team modes, alone in the zone; Capturer is the one
+// whose Progress it is, and it captures the zone once Progress reaches 1. Zones are driven by the KingOfTheHill mode.
+type Zone struct {
+	ID        string
+	Name      string
+	Position  *math32.Vector3
+	Radius    float32
+	Active    bool
+	Owner     string
+	OwnerName string
+	Holder    string
+	Capturer  string
+	Progress  float32
+	Contested bool
+
+	world *World
+}
+
+// ZoneSpawn is a control zone as authored in the map file.
+type ZoneSpawn struct {
+	Name     string
+	Position *math32.Vector3
+	Radius   float32
+}
+
+func NewZone(world *World, name string, position math32.Vector3, radius float32) *Zone {
+	return &Zone{
+		ID:       xid.New().String(),
+		Name:     name,
+		Position: &position,
+		Radius:   radius,
+		Active:   true,
+		world:    world,
+	}
+}
+
+// occupants returns the teams, or the players outside team modes, with a
+// living player inside the zone, along with a display name for each.
+func (z *Zone) occupants(teams bool) ([]string, map[string]string) {
+	groups := make([]string, 0)
+	names := make(map[string]string)
+	for _, player := range z.world.GetPlayers() {
+		if player.Dead || player.Position.DistanceTo(z.Position) > z.Radius {
+			continue
+		}
+		group, name := player.GetID(), player.Name
+		if teams {
+			group, name = player.Team, player.Team
+		}
+		if _, ok := names[group]; !ok {
+			groups = append(groups, group)
+			names[group] = name
+		}
+	}
+	return groups, names
+}
+
+// update advances the capture of the zone and returns the owner scoring
+// this tick, if any. The progress of a capture belongs to its Capturer: it
+// starts over when someone else takes over and fades while the zone is
+// empty.
+func (z *Zone) update(teams bool, deltaTime time.Duration) string {
+	if !z.Active {
+		return ""
+	}
+	groups, names := z.occupants(teams)
+	z.Contested = len(groups) > 1
+	step := float32(deltaTime) / float32(conf.ZoneCaptureTime)
+	if len(groups) == 0 {
+		z.Holder = ""
+		z.fade(step)
+		return ""
+	}
+	if len(groups) > 1 {
+		z.Holder = ""
+		return ""
+	}
+
+	holder := groups[0]
+	z.Holder = holder
+	if holder == z.Owner {
+		z.Progress = 1
+		return holder
+	}
+
+	if z.Owner != "" {
+		// Neutralize the current owner before taking over.
+		z.Progress -= step
+		if z.Progress <= 0 {
+			z.Progress = 0
+			z.Owner = ""
+			z.OwnerName = ""
+			z.Capturer = holder
+		}
+		return ""
+	}
+	if holder != z.Capturer {
+		z.Capturer = holder
+		z.Progress = 0
+	}
+	z.Progress += step
+	if z.Progress >= 1 {
+		z.Progress = 1
+		z.Owner = holder
+		z.OwnerName = names[holder]
+	}
+	return ""
+}
+
+// fade moves an empty zone back to where it was before anyone came in: a
+// partial capture is lost and a partly neutralized owner recovers.
+func (z *Zone) fade(step float32) {
+	if z.Owner != "" {
+		z.Progress = math32.Min(z.Progress+step, 1)
+		return
+	}
+	z.Progress -= step
+	if z.Progress <= 0 {
+		z.Progress = 0
+		z.Capturer = ""
+	}
+}
+
+func (z *Zone) reset(active bool) {
+	z.Active = active
+	z.Owner = ""
+	z.OwnerName = ""
+	z.Holder = ""
+	z.Capturer = ""
+	z.Progress = 0
+	z.Contested = false
+}
+
+func (z *Zone) Refresh(zone Zone) {
+	z.Active = zone.Active
+	z.Owner = zone.Owner
+	z.OwnerName = zone.OwnerName
+	z.Holder = zone.Holder
+	z.Capturer = zone.Capturer
+	z.Progress = zone.Progress
+	z.Contested = zone.Contested
+}
+
+func (z *Zone) Update(deltaTime time.Duration) {
+}
+
+func (z *Zone) UpdatePosition(deltaTime time.Duration) {
+}
+
+func (z Zone) IsDeleted() bool {
+	return false
+}
+
+func (z Zone) GetID() string {
+	return z.ID
+}
+
+func (w *World) AddZone(zone *Zone) {
+	if w.models == nil {
+		w.models = make(map[string]Model)
+	}
+	if zone.world == nil {
+		zone.world = w
+	}
+	w.models[zone.ID] = zone
+	w.events.Publish(&ZoneAddedEvent{Zone: zone})
+}
+
+// GetZones returns the zones sorted by name, so they keep the order of the
+// map file as long as it names them in order.
+func (w *World) GetZones() []*Zone {
+	zones := make([]*Zone, 0)
+	for _, model := range w.models {
+		if zone, ok := model.(*Zone); ok {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Name < zones[j].Name
+	})
+	return zones
+}
+
+func (w *World) GetZone(id string) *Zone {
+	if zone, ok := w.models[id].(*Zone); ok {
+		return zone
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/g3n/engine/math32"
+	"github.com/lambher/video-game/conf"
+)
+
+func TestZoneCapture(t *testing.T) {
+	half := conf.ZoneCaptureTime / 2
+	outside := math32.Vector3{X: 50}
+
+	tests := []struct {
+		name     string
+		steps    []string
+		owner    string
+		progress float32
+	}{
+		{name: "capture", steps: []string{"a", "a"}, owner: "a", progress: 1},
+		{name: "other player starts over", steps: []string{"a", "b"}, owner: "", progress: 0.5},
+		{name: "empty zone fades", steps: []string{"a", "", "a"}, owner: "", progress: 0.5},
+		{name: "contested zone holds", steps: []string{"a", "ab", "a"}, owner: "a", progress: 1},
+		{name: "owner recovers", steps: []string{"a", "a", "b", ""}, owner: "a", progress: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			world := &World{}
+			zone := NewZone(world, "A", math32.Vector3{}, 10)
+			world.AddZone(zone)
+			players := map[string]*Player{
+				"a": NewPlayer("a", world, "a", outside),
+				"b": NewPlayer("b", world, "b", outside),
+			}
+			for _, player := range players {
+				world.AddPlayer(player)
+			}
+
+			for _, inside := range test.steps {
+				for id, player := range players {
+					player.Position = outside.Clone()
+					if strings.Contains(inside, id) {
+						player.Position = math32.NewVec3()
+					}
+				}
+				zone.update(false, half)
+			}
+
+			if zone.Owner != test.owner {
+				t.Errorf("owner = %q, want %q", zone.Owner, test.owner)
+			}
+			if math32.Abs(zone.Progress-test.progress) > 0.01 {
+				t.Errorf("progress = %v, want %v", zone.Progress, test.progress)
+			}
+		})
+	}
+}
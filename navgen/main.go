@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lambher/video-game/conf"
+	"github.com/lambher/video-game/models"
+)
+
+// navgen builds the navigation graph of a map and stores it next to the map
+// file. Run it again whenever the map changes.
+func main() {
+	mapID := flag.String("map", conf.DefaultMap, "name of the map to load from "+conf.MapsDir)
+	flag.Parse()
+
+	worldMap, err := models.LoadMap(*mapID)
+	if err != nil {
+		fmt.Printf("Some error %v\n", err)
+		os.Exit(1)
+	}
+
+	graph := models.BuildNavGraph(worldMap)
+	err = graph.Save()
+	if err != nil {
+		fmt.Printf("Some error %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("map %s: %d nodes, %d edges\n", worldMap.ID, len(graph.Nodes), len(graph.Edges))
+}
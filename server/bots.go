@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lambher/video-game/models"
+	"github.com/rs/xid"
+)
+
+var bots []*models.Bot
+var botCount int
+var botDifficulty *models.Difficulty
+
+// fillBots adds or removes bots so that humans and bots together make
+// botCount players. Bots leave first as humans join.
+func fillBots() {
+	wanted := botCount - len(clients)
+	if wanted < 0 {
+		wanted = 0
+	}
+	for len(bots) < wanted {
+		addBot()
+	}
+	for len(bots) > wanted {
+		removeBot(bots[len(bots)-1])
+	}
+}
+
+// botName returns the lowest "Bot <n>" no current bot is named after, so
+// names stay unique when a bot leaves from the middle of the list.
+func botName() string {
+	taken := make(map[string]bool)
+	for _, bot := range bots {
+		taken[bot.Player.Name] = true
+	}
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("Bot %d", i)
+		if !taken[name] {
+			return name
+		}
+	}
+}
+
+func addBot() {
+	player := models.NewPlayer(xid.New().String(), &world, botName(), world.ChooseSpawnPoint(nil))
+	bot := models.NewBot(&world, player, botDifficulty)
+	bots = append(bots, bot)
+	world.AddPlayer(player)
+	for _, client := range clients {
+		client.addPlayer(player)
+	}
+}
+
+func removeBot(bot *models.Bot) {
+	for i, b := range bots {
+		if b == bot {
+			bots = append(bots[:i], bots[i+1:]...)
+			break
+		}
+	}
+	bot.Remove()
+	world.RemovePlayer(bot.Player)
+	for _, client := range clients {
+		client.sendExit(bot.Player)
+	}
+}
+
+func updateBots(deltaTime time.Duration) {
+	for _, bot := range bots {
+		bot.Update(deltaTime)
+	}
+}
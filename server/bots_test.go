@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lambher/video-game/models"
+)
+
+func TestBotName(t *testing.T) {
+	saved := bots
+	defer func() {
+		bots = saved
+	}()
+	bots = nil
+	join := func() {
+		bots = append(bots, &models.Bot{Player: &models.Player{Name: botName()}})
+	}
+
+	join()
+	join()
+	join()
+	// Bot 2 leaves from the middle of the list.
+	bots = append(bots[:1], bots[2:]...)
+
+	join()
+	join()
+	names := make([]string, 0, len(bots))
+	for _, bot := range bots {
+		names = append(names, bot.Player.Name)
+	}
+	want := []string{"Bot 1", "Bot 3", "Bot 2", "Bot 4"}
+	for i := range want {
+		if i >= len(names) || names[i] != want[i] {
+			t.Fatalf("names = %v, want %v", names, want)
+		}
+	}
+}
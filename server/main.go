@@ -2,6 +2,8 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"net"
 	"strings"
@@ -9,13 +11,13 @@ import (
 
 	"github.com/lambher/video-game/conf"
 
-	"github.com/g3n/engine/math32"
 	"github.com/rs/xid"
 
 	"github.com/lambher/video-game/models"
 )
 
 var world models.World
+var worldMap *models.Map
 
 type Client struct {
 	Addr   *net.UDPAddr
@@ -25,12 +27,74 @@ type Client struct {
 
 var clients map[string]*Client
 
+// onWorldEvent forwards the events of the world to the clients.
+func onWorldEvent(event models.Event) {
+	switch e := event.(type) {
+	case *models.DamageEvent:
+		broadcast("damage", e)
+	case *models.HealthEvent:
+		broadcast("health", e)
+	case *models.Collision:
+		broadcast("collide", e)
+	case *models.DeathEvent:
+		broadcast("death", e)
+		sendScoreboard()
+	case *models.PlayerRespawnedEvent:
+		broadcast("respawn", e.Player)
+	case *models.WeaponStateEvent:
+		for _, client := range clients {
+			if client.Player == e.Player {
+				client.send("ammo", e.State)
+			}
+		}
+	case *models.ProjectileSpawnedEvent:
+		switch projectile := e.Projectile.(type) {
+		case *models.Bullet:
+			broadcast("add_bullet", projectile)
+		case *models.Missile:
+			broadcast("add_missile", projectile)
+		}
+	case *models.HitscanEvent:
+		broadcast("hitscan", e)
+	case *models.ExplosionEvent:
+		broadcast("explosion", e)
+	case *models.PickupSpawnedEvent:
+		broadcast("add_pickup", e.Pickup)
+	case *models.PickupEvent:
+		broadcast("pickup", e)
+	case *models.FlagAddedEvent:
+		broadcast("add_flag", e.Flag)
+	case *models.FlagEvent:
+		broadcast("flag", e)
+	case *models.ZoneAddedEvent:
+		broadcast("add_zone", e.Zone)
+	case *models.EnemySpawnedEvent:
+		broadcast("add_enemy", e.Enemy)
+	case *models.CheckpointEvent:
+		broadcast("checkpoint", e)
+	case *models.GhostEvent:
+		broadcast("ghost", e.Ghost)
+	case *models.MatchState:
+		broadcast("match", e)
+	case *models.RemovedEvent:
+		broadcast("remove_model", e.Model.GetID())
+	}
+}
+
+func broadcast(kind string, v interface{}) {
+	for _, client := range clients {
+		client.send(kind, v)
+	}
+}
+
 func (c *Client) sendResponse() {
+	c.send("map", worldMap.GetInfo())
 	c.addYou()
 	c.sendList()
 	c.populatePlayer()
 
 	world.AddPlayer(c.Player)
+	c.send("ammo", c.Player.GetWeaponState())
 }
 
 func (c *Client) parse(message string) {
@@ -45,12 +109,37 @@ func (c *Client) parse(message string) {
 		c.handleMove([]byte(messages[1]))
 	case "fire":
 		c.handleFire()
+	case "weapon":
+		c.handleWeapon([]byte(messages[1]))
+	case "reload":
+		c.handleReload()
 	}
 }
 
 func (c *Client) handleFire() {
-	c.Player.Fire()
-	c.sendFires()
+	err := c.Player.Fire()
+	if err == nil {
+		return
+	}
+	if rejected := c.Player.GetRejectedShots(); rejected%conf.RejectedShotsWarning == 0 {
+		fmt.Printf("player %s (%s) had %d shots rejected, last: %v\n", c.Player.Name, c.Addr, rejected, err)
+	}
+}
+
+func (c *Client) handleReload() {
+	c.Player.Reload()
+}
+
+func (c *Client) handleWeapon(data []byte) {
+	var name string
+
+	err := json.Unmarshal(data, &name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	c.Player.SwitchWeapon(name)
 }
 
 func (c *Client) handleMove(data []byte) {
@@ -134,22 +223,39 @@ func (c *Client) addPlayer(player *models.Player) {
 func (c *Client) sendList() {
 	for _, player := range world.GetPlayers() {
 		c.addPlayer(player)
+		c.send("health", models.HealthEvent{
+			Player: player.GetID(),
+			HP:     player.GetHP(),
+		})
 	}
-}
-
-func (c *Client) sendFires() {
-	for _, client := range clients {
-		client.sendFire(c.Player)
+	for _, pickup := range world.GetPickups() {
+		c.send("add_pickup", pickup)
+	}
+	for _, flag := range world.GetFlags() {
+		c.send("add_flag", flag)
+	}
+	for _, zone := range world.GetZones() {
+		c.send("add_zone", zone)
+	}
+	for _, enemy := range world.GetEnemies() {
+		c.send("add_enemy", enemy)
+	}
+	if race, ok := world.Match.GetMode().(*models.Race); ok && race.GetGhost() != nil {
+		c.send("ghost", race.GetGhost())
 	}
 }
 
-func (c *Client) sendFire(player *models.Player) {
-	playerData, err := json.Marshal(player)
+func (c *Client) send(kind string, v interface{}) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	data := make([]byte, 0)
 
-	data = append(data, []byte("fire\n")...)
-	data = append(data, playerData...)
+	data = append(data, []byte(kind+"\n")...)
+	data = append(data, payload...)
 
 	_, err = c.Conn.WriteToUDP(data, c.Addr)
 	if err != nil {
@@ -180,8 +286,126 @@ func (c *Client) exit() {
 	}
 }
 
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
 func main() {
+	mapID := flag.String("map", conf.DefaultMap, "name of the map to load from "+conf.MapsDir)
+	scoreLimit := flag.Int("scorelimit", conf.ScoreLimit, "score ending the match, 0 for none")
+	timeLimit := flag.Duration("timelimit", conf.TimeLimit, "duration of the match, 0 for none")
+	modeName := flag.String("mode", conf.DefaultMode, "game mode: ffa, tdm, ctf, koth, tkoth, lms, survival or race")
+	mutators := flag.String("mutators", "", "comma separated mutators: instagib, lowgravity, highknockback, unlimitedammo, doublespeed, vampirism")
+	friendlyFire := flag.String("friendlyfire", conf.FriendlyFire, "damage between teammates: off, on or reflected")
+	flag.IntVar(&botCount, "bots", conf.BotCount, "number of players to fill with bots, 0 for none")
+	difficulty := flag.String("botdifficulty", conf.BotDifficulty, "bot difficulty: easy, normal or hard")
+	flag.Parse()
+
+	var err error
+	worldMap, err = models.LoadMap(*mapID)
+	if err != nil {
+		fmt.Printf("Some error %v\n", err)
+		return
+	}
+
+	world.Weapons, err = models.LoadWeapons(conf.WeaponsFile)
+	if err != nil {
+		fmt.Printf("Some error %v\n", err)
+		return
+	}
+
 	clients = make(map[string]*Client)
+	world.Subscribe(onWorldEvent)
+	world.Level = worldMap.GetLevel()
+	world.SpawnPoints = worldMap.SpawnPoints
+	world.Gates = worldMap.Gates
+	world.Nav, err = models.LoadNavGraph(worldMap)
+	if err != nil {
+		// Bots still work without it, they just fly straight.
+		fmt.Printf("no navigation graph for map %s: %v\n", worldMap.ID, err)
+	}
+	world.NoWeapons = worldMap.DisableWeapons
+	for _, spawn := range worldMap.Pickups {
+		world.AddPickup(models.NewPickup(&world, spawn.Kind, *spawn.Position))
+	}
+	for i := 0; i < conf.RandomPickups; i++ {
+		world.AddPickup(models.NewRandomPickup(&world))
+	}
+	mode, err := models.NewMode(*modeName, &world)
+	if err != nil {
+		fmt.Printf("Some error %v\n", err)
+		return
+	}
+	if *modeName == models.ModeCaptureTheFlag {
+		if len(worldMap.Flags) == 0 {
+			fmt.Printf("Some error map %s has no flags\n", worldMap.ID)
+			return
+		}
+		for _, base := range worldMap.Flags {
+			world.AddFlag(models.NewFlag(&world, base.Team, *base.Position))
+		}
+	}
+	if *modeName == models.ModeRace && len(worldMap.Gates) == 0 {
+		fmt.Printf("Some error map %s has no gates\n", worldMap.ID)
+		return
+	}
+	if *modeName == models.ModeKingOfTheHill || *modeName == models.ModeTeamKingOfTheHill {
+		if len(worldMap.Zones) == 0 {
+			fmt.Printf("Some error map %s has no zones\n", worldMap.ID)
+			return
+		}
+		for _, spawn := range worldMap.Zones {
+			world.AddZone(models.NewZone(&world, spawn.Name, *spawn.Position, spawn.Radius))
+		}
+		mode.Reset()
+	}
+	if *mutators != "" {
+		for _, name := range strings.Split(*mutators, ",") {
+			mutator, err := models.NewMutator(strings.TrimSpace(name))
+			if err != nil {
+				fmt.Printf("Some error %v\n", err)
+				return
+			}
+			world.AddMutator(mutator)
+		}
+	}
+	switch *friendlyFire {
+	case models.FriendlyFireOff, models.FriendlyFireOn, models.FriendlyFireReflected:
+		world.FriendlyFire = *friendlyFire
+	default:
+		fmt.Printf("Some error unknown friendly fire setting %q\n", *friendlyFire)
+		return
+	}
+	botDifficulty, err = models.GetDifficulty(*difficulty)
+	if err != nil {
+		fmt.Printf("Some error %v\n", err)
+		return
+	}
+	world.Match = models.NewMatch(&world, mode, *scoreLimit, *timeLimit)
+	if *modeName == models.ModeSurvival {
+		// Survival is cooperative and lasts until the players are wiped out.
+		world.Match.MinPlayers = 1
+		world.Match.ScoreLimit = 0
+		world.Match.TimeLimit = 0
+	}
+	if *modeName == models.ModeLastManStanding {
+		// Scores count the players eliminated so far: only the last survivor
+		// ends the round.
+		world.Match.ScoreLimit = 0
+	}
+	if *modeName == models.ModeRace {
+		// A time trial can be run alone, against the ghost of the best lap.
+		world.Match.MinPlayers = 1
+		if !isFlagSet("scorelimit") {
+			world.Match.ScoreLimit = conf.RaceLaps
+		}
+	}
 	addr := net.UDPAddr{
 		Port: conf.Port,
 		IP:   net.ParseIP(conf.Host),
@@ -195,56 +419,91 @@ func main() {
 	fmt.Printf("listen on port %d\n", addr.Port)
 
 	go gameLoop()
-	go tick()
+	serve(ser)
+}
 
+// serve reads the messages of the clients until the connection is closed
+// and queues them for gameLoop.
+func serve(ser *net.UDPConn) {
 	for {
 		p := make([]byte, 2048)
 
 		n, remoteaddr, err := ser.ReadFromUDP(p)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
 		if err != nil {
 			fmt.Printf("Some error  %v", err)
 			continue
 		}
 		value := string(p[:n])
-		fmt.Printf("Read a message from %s %s \n", remoteaddr.String(), p)
-		if value == "hello" {
-			player := models.NewPlayer(xid.New().String(), &world, "", *math32.NewVec3())
-			clients[remoteaddr.String()] = &Client{
-				Addr:   remoteaddr,
-				Conn:   ser,
-				Player: player,
-			}
-			go clients[remoteaddr.String()].sendResponse()
-			//go clients[remoteaddr.String()].listen()
-		} else if value == "exit" {
-			if client, ok := clients[remoteaddr.String()]; ok {
-				client.exit()
-				delete(clients, remoteaddr.String())
-			}
-		} else {
-			if client, ok := clients[remoteaddr.String()]; ok {
-				client.parse(value)
-			}
+		commands <- func() {
+			handleMessage(ser, remoteaddr, value)
+		}
+	}
+}
+
+// commands holds the work the read loop hands over to gameLoop. gameLoop is
+// the only goroutine touching the world and the clients, so everything else
+// goes through this queue.
+var commands = make(chan func(), conf.CommandQueueSize)
+
+func handleMessage(ser *net.UDPConn, remoteaddr *net.UDPAddr, value string) {
+	if value == "hello" {
+		player := models.NewPlayer(xid.New().String(), &world, "", world.ChooseSpawnPoint(nil))
+		clients[remoteaddr.String()] = &Client{
+			Addr:   remoteaddr,
+			Conn:   ser,
+			Player: player,
+		}
+		clients[remoteaddr.String()].sendResponse()
+	} else if value == "exit" {
+		if client, ok := clients[remoteaddr.String()]; ok {
+			client.exit()
+			delete(clients, remoteaddr.String())
+		}
+	} else {
+		if client, ok := clients[remoteaddr.String()]; ok {
+			client.parse(value)
 		}
 	}
 }
 
 func gameLoop() {
-	tick := time.Tick(16 * time.Millisecond)
+	tick := time.Tick(conf.TickTimeWorld)
+	refresh := time.Tick(conf.TickTimeServer)
+	scoreboard := time.Tick(conf.ScoreboardInterval)
+	fill := time.Tick(conf.BotFillInterval)
 	t := time.Now()
 	for {
 		select {
+		case command := <-commands:
+			command()
 		case <-tick:
 			deltaTime := time.Since(t)
 			t = time.Now()
+			updateBots(deltaTime)
 			world.Update(deltaTime)
+		case <-refresh:
+			refreshPlayers()
+		case <-scoreboard:
+			sendScoreboard()
+		case <-fill:
+			fillBots()
 		}
 	}
 }
 
-func tick() {
-	for range time.Tick(conf.TickTimeServer) {
-		refreshPlayers()
+func sendScoreboard() {
+	scoreboard := world.GetScoreboard()
+	state := world.Match.GetState()
+	survival, _ := world.Match.GetMode().(*models.Survival)
+	for _, client := range clients {
+		client.send("scoreboard", scoreboard)
+		client.send("match", state)
+		if survival != nil {
+			client.send("wave", survival.GetWave())
+		}
 	}
 }
 
@@ -253,5 +512,17 @@ func refreshPlayers() {
 		for _, player := range world.GetPlayers() {
 			client.refreshPlayer(player)
 		}
+		for _, missile := range world.GetMissiles() {
+			client.send("refresh_missile", missile)
+		}
+		for _, zone := range world.GetZones() {
+			client.send("refresh_zone", zone)
+		}
+		for _, enemy := range world.GetEnemies() {
+			client.send("refresh_enemy", enemy)
+		}
+		if world.SafeZone != nil {
+			client.send("safe_zone", world.SafeZone)
+		}
 	}
 }
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lambher/video-game/conf"
+	"github.com/lambher/video-game/models"
+)
+
+// TestConcurrentClients has clients join, fly, fire, switch weapons and
+// leave at the same time while bots fill the arena. Run it with -race: every
+// access to the world and the clients must go through the command queue.
+func TestConcurrentClients(t *testing.T) {
+	err := os.Chdir("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worldMap, err = models.LoadMap(conf.DefaultMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.Weapons, err = models.LoadWeapons(conf.WeaponsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.Level = worldMap.GetLevel()
+	world.SpawnPoints = worldMap.SpawnPoints
+	world.Nav, _ = models.LoadNavGraph(worldMap)
+	world.Subscribe(onWorldEvent)
+	mode, err := models.NewMode(conf.DefaultMode, &world)
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.Match = models.NewMatch(&world, mode, 0, 0)
+	world.Match.MinPlayers = 1
+	clients = make(map[string]*Client)
+	botCount = 6
+	botDifficulty, _ = models.GetDifficulty(conf.BotDifficulty)
+
+	ser, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ser.Close()
+	go gameLoop()
+	go serve(ser)
+
+	moveData, err := json.Marshal(&models.Moves{Keys: map[string]bool{
+		models.MoveForward: true,
+		models.TurnLeft:    true,
+	}, VerticalAngleAngleSpeed: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	weaponData, _ := json.Marshal("railgun")
+	messages := []string{
+		"move\n" + string(moveData),
+		"fire\n",
+		"weapon\n" + string(weaponData),
+		"fire\n",
+		"reload\n",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := net.DialUDP("udp", nil, ser.LocalAddr().(*net.UDPAddr))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+			go drain(conn)
+
+			send(t, conn, "hello")
+			time.Sleep(time.Millisecond * 100)
+			for j := 0; j < 50; j++ {
+				send(t, conn, messages[j%len(messages)])
+				time.Sleep(conf.TickTimeClient / 5)
+			}
+			send(t, conn, "exit")
+		}()
+	}
+	wg.Wait()
+
+	// Bots are added once per conf.BotFillInterval, give them time to take
+	// the seats of the clients that left.
+	time.Sleep(conf.BotFillInterval * 2)
+	state := make(chan string)
+	commands <- func() {
+		state <- fmt.Sprintf("%d clients, %d players", len(clients), len(world.GetPlayers()))
+	}
+	if got, want := <-state, fmt.Sprintf("0 clients, %d players", botCount); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func send(t *testing.T, conn *net.UDPConn, message string) {
+	_, err := conn.Write([]byte(message))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// drain reads what the server sends until the connection is closed, like a
+// real client would.
+func drain(conn *net.UDPConn) {
+	p := make([]byte, conf.MaxPacketSize)
+	for {
+		if _, err := conn.Read(p); err != nil {
+			return
+		}
+	}
+}